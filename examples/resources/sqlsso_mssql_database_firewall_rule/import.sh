@@ -0,0 +1,2 @@
+# MS SQL database firewall rules can be imported using <sql_server_dns>:<database>:<port>/<rule_name>
+terraform import sqlsso_mssql_database_firewall_rule.office example-sqlserver.database.windows.net:example-db:1433/office
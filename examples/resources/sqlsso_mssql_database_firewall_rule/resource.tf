@@ -0,0 +1,9 @@
+provider "sqlsso" {}
+
+resource "sqlsso_mssql_database_firewall_rule" "office" {
+  sql_server_dns   = "example-sqlserver.database.windows.net"
+  database         = "example-db"
+  rule_name        = "office"
+  start_ip_address = "203.0.113.0"
+  end_ip_address   = "203.0.113.255"
+}
@@ -0,0 +1,2 @@
+# MS SQL database permissions can be imported using <sql_server_dns>:<database>:<port>/<principal_name>
+terraform import sqlsso_mssql_database_permission.monitoring example-sqlserver.database.windows.net:example-db:1433/monitoring
@@ -0,0 +1,22 @@
+provider "sqlsso" {}
+
+# This will require the right permissions, see azuread_service_principal
+data "azuread_service_principal" "monitoring" {
+  display_name = "monitoring"
+}
+
+resource "sqlsso_mssql_server_aad_account" "monitoring" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  account_name   = data.azuread_service_principal.monitoring.display_name
+  principal_kind = "service_principal"
+  client_id      = data.azuread_service_principal.monitoring.client_id
+  role           = "reader"
+}
+
+resource "sqlsso_mssql_database_permission" "monitoring" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  principal_name = sqlsso_mssql_server_aad_account.monitoring.account_name
+  permissions    = ["VIEW DATABASE STATE", "SHOWPLAN"]
+}
@@ -0,0 +1,2 @@
+# MS SQL database roles can be imported using <sql_server_dns>:<database>:<port>/<role_name>
+terraform import sqlsso_mssql_database_role.reporting example-sqlserver.database.windows.net:example-db:1433/app_reporting
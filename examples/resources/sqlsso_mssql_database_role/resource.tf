@@ -0,0 +1,52 @@
+provider "azurerm" {
+  features {}
+}
+
+provider "sqlsso" {}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "example-resources"
+  location = "West Europe"
+}
+
+resource "azurerm_mssql_server" "example" {
+  name                = "example-sqlserver"
+  resource_group_name = azurerm_resource_group.example.name
+  location            = azurerm_resource_group.example.location
+  version             = "12.0"
+  minimum_tls_version = "1.2"
+
+  azuread_administrator {
+    login_username              = "AzureAD Admin"
+    object_id                   = data.azurerm_client_config.current.object_id
+    azuread_authentication_only = true
+  }
+}
+
+resource "azurerm_mssql_database" "example" {
+  name      = "example-db"
+  server_id = azurerm_mssql_server.example.id
+}
+
+resource "sqlsso_mssql_database_role" "reporting" {
+  sql_server_dns = azurerm_mssql_server.example.fully_qualified_domain_name
+  database       = azurerm_mssql_database.example.name
+  role_name      = "app_reporting"
+  member_of      = ["db_datareader"]
+}
+
+# This will require the right permissions, see azuread_group
+data "azuread_group" "analysts" {
+  display_name = "analysts"
+}
+
+resource "sqlsso_mssql_server_aad_account" "analysts" {
+  sql_server_dns = azurerm_mssql_server.example.fully_qualified_domain_name
+  database       = azurerm_mssql_database.example.name
+  account_name   = data.azuread_group.analysts.display_name
+  principal_kind = "group"
+  object_id      = data.azuread_group.analysts.object_id
+  role           = sqlsso_mssql_database_role.reporting.role_name
+}
@@ -0,0 +1,2 @@
+# MS SQL permissions can be imported using <sql_server_dns>:<database>:<port>/<principal_name>/<SCHEMA|OBJECT>::<securable_name>, with / and % in names written as %2F and %25
+terraform import sqlsso_mssql_permission.reporting example-sqlserver.database.windows.net:example-db:1433/reporting/SCHEMA::sales
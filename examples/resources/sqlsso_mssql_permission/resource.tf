@@ -0,0 +1,29 @@
+provider "sqlsso" {}
+
+resource "sqlsso_mssql_database_role" "reporting" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  role_name      = "reporting"
+}
+
+# Read access to everything in the sales schema
+resource "sqlsso_mssql_permission" "reporting" {
+  sql_server_dns  = "example-sqlserver.database.windows.net"
+  database        = "example-db"
+  principal_name  = sqlsso_mssql_database_role.reporting.role_name
+  securable_class = "schema"
+  securable_name  = "sales"
+  permissions     = ["SELECT", "VIEW DEFINITION"]
+}
+
+# Hide the salary column of the employees table
+resource "sqlsso_mssql_permission" "salary" {
+  sql_server_dns  = "example-sqlserver.database.windows.net"
+  database        = "example-db"
+  principal_name  = sqlsso_mssql_database_role.reporting.role_name
+  securable_class = "object"
+  securable_name  = "hr.employees"
+  columns         = ["salary"]
+  permissions     = ["SELECT"]
+  state           = "deny"
+}
@@ -0,0 +1,2 @@
+# MS SQL role members can be imported using <sql_server_dns>:<database>:<port>/<role_name>/<member_name>
+terraform import sqlsso_mssql_role_member.dba_user example-sqlserver.database.windows.net:example-db:1433/app_reporting/jane.doe@example.com
@@ -0,0 +1,17 @@
+provider "sqlsso" {}
+
+# Add a user created by a DBA to a role managed by terraform
+resource "sqlsso_mssql_role_member" "dba_user" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  role_name      = "app_reporting"
+  member_name    = "jane.doe@example.com"
+}
+
+# Nest one role in another
+resource "sqlsso_mssql_role_member" "nested" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  role_name      = "db_datareader"
+  member_name    = "app_reporting"
+}
@@ -0,0 +1,2 @@
+# MS SQL role members can be imported using <sql_server_dns>:<database>:<port>/<role_name>
+terraform import sqlsso_mssql_role_members.owners example-sqlserver.database.windows.net:example-db:1433/db_owner
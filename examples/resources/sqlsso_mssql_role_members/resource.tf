@@ -0,0 +1,18 @@
+provider "sqlsso" {}
+
+resource "sqlsso_mssql_server_aad_account" "deployer" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  account_name   = "deployer"
+  creation_mode  = "external_provider"
+  role           = "reader"
+}
+
+# Only the deployer and the break-glass group may own the database
+resource "sqlsso_mssql_role_members" "owners" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  role_name      = "db_owner"
+  members        = [sqlsso_mssql_server_aad_account.deployer.account_name]
+  ignore_members = ["break-glass-admins"]
+}
@@ -0,0 +1,2 @@
+# MS SQL schemas can be imported using <sql_server_dns>:<database>:<port>/<schema_name>
+terraform import sqlsso_mssql_schema.orders example-sqlserver.database.windows.net:example-db:1433/orders
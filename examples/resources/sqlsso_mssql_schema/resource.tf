@@ -0,0 +1,23 @@
+provider "sqlsso" {}
+
+# This will require the right permissions, see azuread_service_principal
+data "azuread_service_principal" "orders" {
+  display_name = "orders-service"
+}
+
+resource "sqlsso_mssql_server_aad_account" "orders" {
+  sql_server_dns = "example-sqlserver.database.windows.net"
+  database       = "example-db"
+  account_name   = data.azuread_service_principal.orders.display_name
+  principal_kind = "service_principal"
+  client_id      = data.azuread_service_principal.orders.client_id
+  role           = "reader"
+}
+
+resource "sqlsso_mssql_schema" "orders" {
+  sql_server_dns     = "example-sqlserver.database.windows.net"
+  database           = "example-db"
+  schema_name        = "orders"
+  owner              = sqlsso_mssql_server_aad_account.orders.account_name
+  on_destroy_objects = "transfer"
+}
@@ -60,6 +60,26 @@ resource "sqlsso_mssql_server_aad_account" "example" {
   sql_server_dns = azurerm_mssql_server.example.fully_qualified_domain_name
   database       = azurerm_mssql_database.example.name
   account_name   = azurerm_linux_web_app.example.name
-  object_id      = data.azuread_service_principal.example.application_id
+  principal_kind = "managed_identity"
+  client_id      = data.azuread_service_principal.example.client_id
   role           = "owner"
-}
\ No newline at end of file
+}
+# Synapse SQL pools need the flavor to use their own T-SQL
+resource "sqlsso_mssql_server_aad_account" "synapse" {
+  sql_server_dns = "example-workspace.sql.azuresynapse.net"
+  database       = "example-pool"
+  account_name   = azurerm_linux_web_app.example.name
+  flavor         = "synapse_dedicated"
+  creation_mode  = "external_provider"
+  role           = "reader"
+}
+
+# Accounts in a failover group are created through the listener and verified on the secondary
+resource "sqlsso_mssql_server_aad_account" "geo" {
+  sql_server_dns  = "example-fog.database.windows.net"
+  database        = "example-db"
+  account_name    = azurerm_linux_web_app.example.name
+  principal_kind  = "managed_identity"
+  client_id       = data.azuread_service_principal.example.client_id
+  verify_replicas = ["example-sqlserver-secondary.database.windows.net"]
+}
@@ -0,0 +1,2 @@
+# MS SQL logins can be imported using <sql_server_dns>:<port>/<login_name>
+terraform import sqlsso_mssql_server_aad_login.example example-sqlserver.database.windows.net:1433/sql-monitoring
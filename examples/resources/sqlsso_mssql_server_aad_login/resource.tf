@@ -0,0 +1,38 @@
+provider "azurerm" {
+  features {}
+}
+
+provider "sqlsso" {}
+
+data "azurerm_client_config" "current" {}
+
+resource "azurerm_resource_group" "example" {
+  name     = "example-resources"
+  location = "West Europe"
+}
+
+resource "azurerm_mssql_server" "example" {
+  name                = "example-sqlserver"
+  resource_group_name = azurerm_resource_group.example.name
+  location            = azurerm_resource_group.example.location
+  version             = "12.0"
+  minimum_tls_version = "1.2"
+
+  azuread_administrator {
+    login_username              = "AzureAD Admin"
+    object_id                   = data.azurerm_client_config.current.object_id
+    azuread_authentication_only = true
+  }
+}
+
+# This will require the right permissions, see azuread_group
+data "azuread_group" "monitoring" {
+  display_name = "sql-monitoring"
+}
+
+resource "sqlsso_mssql_server_aad_login" "example" {
+  sql_server_dns = azurerm_mssql_server.example.fully_qualified_domain_name
+  login_name     = data.azuread_group.monitoring.display_name
+  object_id      = data.azuread_group.monitoring.object_id
+  server_roles   = ["##MS_ServerStateReader##"]
+}
@@ -0,0 +1,2 @@
+# Postgres role members can be imported using <sql_server_dns>:<port>/<role_name>
+terraform import sqlsso_postgresql_role_members.monitor example-psqlserver.postgres.database.azure.com:5432/pg_monitor
@@ -0,0 +1,17 @@
+provider "sqlsso" {}
+
+resource "sqlsso_postgresql_server_aad_account" "monitoring" {
+  sql_server_dns = "example-psqlserver.postgres.database.azure.com"
+  database       = "example-db"
+  user_name      = "AzureAD Admin"
+  account_name   = "monitoring"
+}
+
+# Only the monitoring identity may read the server statistics
+resource "sqlsso_postgresql_role_members" "monitor" {
+  sql_server_dns = "example-psqlserver.postgres.database.azure.com"
+  user_name      = "AzureAD Admin"
+  role_name      = "pg_monitor"
+  members        = [sqlsso_postgresql_server_aad_account.monitoring.account_name]
+  ignore_members = ["azure_pg_admin"]
+}
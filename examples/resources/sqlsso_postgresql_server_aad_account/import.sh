@@ -0,0 +1,3 @@
+# Postgres accounts can be imported using <sql_server_dns>:<database>:<port>/<account_name>,
+# the account terraform logs in with is taken from the access token unless SQLSSO_POSTGRESQL_USER_NAME is set
+terraform import sqlsso_postgresql_server_aad_account.example example-psqlserver.postgres.database.azure.com:example-db:5432/example-linux-web-app
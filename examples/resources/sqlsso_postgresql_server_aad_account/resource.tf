@@ -77,7 +77,19 @@ data "azuread_service_principal" "example" {
 resource "sqlsso_postgresql_server_aad_account" "example" {
   sql_server_dns = azurerm_postgresql_flexible_server.example.fully_qualified_domain_name
   database       = azurerm_postgresql_flexible_server_database.example.name
-  user_name      = data.azurerm_client_config.current.display_name
   account_name   = azurerm_linux_web_app.example.name
-  role           = "owner"
+  object_id      = azurerm_linux_web_app.example.identity[0].principal_id
+  principal_type = "service"
+
+  database_privileges = ["CONNECT", "CREATE", "TEMPORARY"]
+  member_of           = ["pg_read_all_data", "pg_write_all_data"]
+
+  connection_limit = 20
+  settings = {
+    search_path       = "app, public"
+    statement_timeout = "30s"
+  }
+
+  # The app creates its own tables, hand them to the administrator when it is removed
+  on_destroy_ownership = "reassign"
 }
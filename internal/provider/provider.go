@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
 var (
@@ -28,6 +29,7 @@ type sqlssoProvider struct {
 }
 
 type sqlssoProviderModel struct {
+	MaintenanceDatabase types.String `tfsdk:"maintenance_database"`
 }
 
 func (p *sqlssoProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -37,7 +39,12 @@ func (p *sqlssoProvider) Metadata(_ context.Context, _ provider.MetadataRequest,
 
 func (p *sqlssoProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Attributes: map[string]schema.Attribute{},
+		Attributes: map[string]schema.Attribute{
+			"maintenance_database": schema.StringAttribute{
+				Description: "The Postgres database Azure AD principals are created and dropped on, `postgres` by default. Use it when the account terraform runs as cannot connect to `postgres`.",
+				Optional:    true,
+			},
+		},
 	}
 }
 
@@ -48,6 +55,10 @@ func (p *sqlssoProvider) Configure(ctx context.Context, req provider.ConfigureRe
 	if resp.Diagnostics.HasError() {
 		return
 	}
+
+	resp.ResourceData = sqlsso.ProviderData{
+		MaintenanceDatabase: config.MaintenanceDatabase.ValueString(),
+	}
 }
 
 func (p *sqlssoProvider) DataSources(_ context.Context) []func() datasource.DataSource {
@@ -58,5 +69,14 @@ func (p *sqlssoProvider) Resources(_ context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		sqlsso.NewMssql,
 		sqlsso.NewPostgre,
+		sqlsso.NewMssqlLogin,
+		sqlsso.NewMssqlRole,
+		sqlsso.NewMssqlRoleMember,
+		sqlsso.NewMssqlSchema,
+		sqlsso.NewMssqlPermission,
+		sqlsso.NewMssqlDatabasePermission,
+		sqlsso.NewMssqlFirewallRule,
+		sqlsso.NewMssqlRoleMembers,
+		sqlsso.NewPostgreRoleMembers,
 	}
 }
@@ -0,0 +1,78 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var ifExistsMap = map[string]struct{}{"fail": {}, "adopt": {}, "recreate": {}}
+var deleteBehaviorMap = map[string]struct{}{"drop": {}, "disable": {}, "revoke_roles": {}}
+var destroyOwnershipMap = map[string]struct{}{"fail": {}, "reassign": {}}
+
+// accountConnection is the part of a connection needed to create an account which may already exist.
+type accountConnection interface {
+	CreateAccount(context.Context, *diag.Diagnostics)
+	AdoptAccount(context.Context, *diag.Diagnostics)
+	DropAccount(context.Context, *diag.Diagnostics)
+}
+
+func ifExistsAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "What to do when the account already exists: `fail` returns an error, `adopt` takes over management of the existing account (after verifying it is the same principal) and `recreate` drops and recreates it.",
+		Optional:    true,
+		Computed:    true,
+		Default:     stringdefault.StaticString("fail"),
+		Validators: []validator.String{
+			stringInMap(ifExistsMap),
+		},
+	}
+}
+
+func deleteBehaviorAttribute(disable string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: fmt.Sprintf("What happens to the account on destroy: `drop` removes it, `disable` %s and strips its roles while keeping the account and anything it owns, and `revoke_roles` only strips its roles.", disable),
+		Optional:    true,
+		Computed:    true,
+		Default:     stringdefault.StaticString("drop"),
+		Validators: []validator.String{
+			stringInMap(deleteBehaviorMap),
+		},
+	}
+}
+
+// createAccount creates the account or, when it already exists, handles it as configured by ifExists. verify is
+// called before an existing account is adopted and should add an error if it is not the configured principal.
+func createAccount(ctx context.Context, conn accountConnection, account string, ifExists string, exists bool, verify func(*diag.Diagnostics), diags *diag.Diagnostics) {
+	if !exists {
+		conn.CreateAccount(ctx, diags)
+		return
+	}
+
+	switch ifExists {
+	case "adopt":
+		verify(diags)
+		if diags.HasError() {
+			return
+		}
+
+		conn.AdoptAccount(ctx, diags)
+	case "recreate":
+		conn.DropAccount(ctx, diags)
+		if diags.HasError() {
+			return
+		}
+
+		conn.CreateAccount(ctx, diags)
+	default:
+		diags.AddError(
+			"Account already exists",
+			fmt.Sprintf("The account %q already exists in the database. Set %q to %q to take over management of the existing account or to %q to drop and recreate it.",
+				account, ifExistsProp, "adopt", "recreate"),
+		)
+	}
+}
@@ -8,3 +8,45 @@ const objectIdProp string = "object_id"
 const accountTypeProp string = "account_type"
 const roleProp string = "role"
 const userNameProp string = "user_name"
+const creationModeProp string = "creation_mode"
+const principalKindProp string = "principal_kind"
+const clientIdProp string = "client_id"
+const ifExistsProp string = "if_exists"
+const onDestroyOwnershipProp string = "on_destroy_ownership"
+const reassignOwnedToProp string = "reassign_owned_to"
+const deleteBehaviorProp string = "delete_behavior"
+const loginNameProp string = "login_name"
+const defaultDatabaseProp string = "default_database"
+const serverRolesProp string = "server_roles"
+const roleNameProp string = "role_name"
+const ownerProp string = "owner"
+const memberOfProp string = "member_of"
+const memberNameProp string = "member_name"
+const schemaNameProp string = "schema_name"
+const onDestroyObjectsProp string = "on_destroy_objects"
+const transferObjectsToProp string = "transfer_objects_to"
+const principalNameProp string = "principal_name"
+const securableClassProp string = "securable_class"
+const securableNameProp string = "securable_name"
+const columnsProp string = "columns"
+const permissionsProp string = "permissions"
+const permissionStateProp string = "state"
+const withGrantOptionProp string = "with_grant_option"
+const ruleNameProp string = "rule_name"
+const startIpAddressProp string = "start_ip_address"
+const endIpAddressProp string = "end_ip_address"
+const flavorProp string = "flavor"
+const verifyReplicasProp string = "verify_replicas"
+const replicationTimeoutProp string = "replication_timeout"
+const membersProp string = "members"
+const ignoreMembersProp string = "ignore_members"
+const principalTypeProp string = "principal_type"
+const isAdminProp string = "is_admin"
+const isMfaProp string = "is_mfa"
+const databasePrivilegesProp string = "database_privileges"
+const maintenanceDatabaseProp string = "maintenance_database"
+const connectionLimitProp string = "connection_limit"
+const createDatabaseProp string = "create_database"
+const createRoleProp string = "create_role"
+const inheritProp string = "inherit"
+const settingsProp string = "settings"
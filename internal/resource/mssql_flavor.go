@@ -0,0 +1,47 @@
+package resource
+
+import (
+	"strings"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var mssqlFlavorMap = map[string]ssoSql.MssqlFlavor{
+	"azure_sql_database": ssoSql.AzureSqlDatabase,
+	"managed_instance":   ssoSql.ManagedInstance,
+	"synapse_dedicated":  ssoSql.SynapseDedicated,
+	"synapse_serverless": ssoSql.SynapseServerless,
+}
+
+// mssqlFlavorAttribute selects the T-SQL dialect, it only changes the statements sent so it never replaces anything.
+// notes describes what the flavor changes for the resource and may be empty.
+func mssqlFlavorAttribute(notes string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: strings.TrimSpace("The kind of server: `azure_sql_database`, `managed_instance`, `synapse_dedicated` or `synapse_serverless`. " + notes),
+		Optional:    true,
+		Computed:    true,
+		Default:     stringdefault.StaticString("azure_sql_database"),
+		Validators: []validator.String{
+			stringInMap(mssqlFlavorMap),
+		},
+	}
+}
+
+func isSynapseFlavor(flavor string) bool {
+	return mssqlFlavorMap[flavor] == ssoSql.SynapseDedicated || mssqlFlavorMap[flavor] == ssoSql.SynapseServerless
+}
+
+// mssqlFlavorOrDefault returns the flavor, or azure_sql_database when there is none. Imported resources and state
+// written before the flavor attribute existed have no flavor until they are read.
+func mssqlFlavorOrDefault(flavor types.String) types.String {
+	if flavor.IsNull() {
+		return types.StringValue("azure_sql_database")
+	}
+
+	return flavor
+}
@@ -0,0 +1,243 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+	"terraform-provider-sqlsso/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var permissionStateMap = map[string]struct{}{"grant": {}, "deny": {}}
+
+// mssqlPermissionModel holds the attributes shared by sqlsso_mssql_permission and sqlsso_mssql_database_permission.
+type mssqlPermissionModel struct {
+	ID              types.String `tfsdk:"id"`
+	SqlServer       types.String `tfsdk:"sql_server_dns"`
+	Database        types.String `tfsdk:"database"`
+	Port            types.Int64  `tfsdk:"port"`
+	Flavor          types.String `tfsdk:"flavor"`
+	PrincipalName   types.String `tfsdk:"principal_name"`
+	Permissions     types.Set    `tfsdk:"permissions"`
+	State           types.String `tfsdk:"state"`
+	WithGrantOption types.Bool   `tfsdk:"with_grant_option"`
+}
+
+// mssqlSecurable is what the permissions are on: the database (class DATABASE), a schema or an object, optionally
+// limited to some of its columns.
+type mssqlSecurable struct {
+	class   string
+	name    string
+	columns []string
+}
+
+func permissionStateAttribute() schema.StringAttribute {
+	return schema.StringAttribute{
+		Description: "Whether the permissions are granted (`grant`) or denied (`deny`).",
+		Optional:    true,
+		Computed:    true,
+		Default:     stringdefault.StaticString("grant"),
+		Validators: []validator.String{
+			stringInMap(permissionStateMap),
+		},
+	}
+}
+
+func withGrantOptionAttribute() schema.BoolAttribute {
+	return schema.BoolAttribute{
+		Description: "Allows the principal to grant the permissions to others, only valid for granted permissions.",
+		Optional:    true,
+		Computed:    true,
+		Default:     booldefault.StaticBool(false),
+	}
+}
+
+// validate checks that the grant option is not combined with denied permissions.
+func (m mssqlPermissionModel) validate(diags *diag.Diagnostics) {
+	if m.WithGrantOption.ValueBool() && m.State.ValueString() == "deny" {
+		diags.AddAttributeError(
+			path.Root(withGrantOptionProp),
+			"Unexpected grant option",
+			fmt.Sprintf("%q cannot be combined with denied permissions.", withGrantOptionProp),
+		)
+	}
+}
+
+func (m mssqlPermissionModel) connection(securable mssqlSecurable) permissionConnection {
+	conn := ssoSql.CreateMssqlPermission(ssoSql.CreateMssqlServer(m.SqlServer.ValueString(), m.Database.ValueString(), m.Port.ValueInt64(), mssqlFlavorMap[m.Flavor.ValueString()]), m.PrincipalName.ValueString(), securable.class, securable.name, securable.columns)
+	return &conn
+}
+
+// primaryConnection returns the connection for changing the permissions, which are written on the primary.
+func (m mssqlPermissionModel) primaryConnection(ctx context.Context, securable mssqlSecurable, diags *diag.Diagnostics) permissionConnection {
+	conn := m.connection(securable)
+	conn.UsePrimary(ctx, diags)
+	return conn
+}
+
+// permissionConnection is the part of a permission connection used by the resources.
+type permissionConnection interface {
+	UsePrimary(context.Context, *diag.Diagnostics)
+	ReadPermissions(context.Context, *diag.Diagnostics) []ssoSql.MssqlPermission
+	Grant(ctx context.Context, diags *diag.Diagnostics, permissions []string, withGrantOption bool)
+	Deny(ctx context.Context, diags *diag.Diagnostics, permissions []string)
+	Revoke(ctx context.Context, diags *diag.Diagnostics, permissions []string, cascade bool)
+	Id() string
+}
+
+// read refreshes the permissions present on the securable and reports whether there are any left.
+func (m *mssqlPermissionModel) read(ctx context.Context, securable mssqlSecurable, diags *diag.Diagnostics) bool {
+	found := m.connection(securable).ReadPermissions(ctx, diags)
+	if diags.HasError() {
+		return false
+	}
+
+	m.Flavor = mssqlFlavorOrDefault(m.Flavor)
+
+	var current []string
+	diags.Append(m.Permissions.ElementsAs(ctx, &current, false)...)
+	if diags.HasError() {
+		return false
+	}
+
+	// Every user is granted CONNECT, it is only reported when it is managed here
+	if securable.class == "DATABASE" && !slices.Contains(current, "CONNECT") {
+		found = slices.DeleteFunc(found, func(p ssoSql.MssqlPermission) bool { return p.Permission == "CONNECT" })
+	}
+
+	// Imported permissions have no state yet, denied permissions are only picked when nothing is granted
+	if m.State.IsNull() {
+		m.State = types.StringValue("deny")
+		if slices.ContainsFunc(found, func(p ssoSql.MssqlPermission) bool { return p.State != "D" }) {
+			m.State = types.StringValue("grant")
+		}
+	}
+
+	permissions, withGrantOption := presentPermissions(found, securable.columns, m.State.ValueString() == "deny")
+
+	if len(permissions) == 0 {
+		return false
+	}
+
+	permissionSet, d := types.SetValueFrom(ctx, types.StringType, permissions)
+	diags.Append(d...)
+	m.Permissions = permissionSet
+	m.WithGrantOption = types.BoolValue(withGrantOption)
+
+	return true
+}
+
+// presentPermissions returns the permissions which are granted (or denied) on the securable itself or, when columns
+// are given, on each of the columns. The grant option is only reported when all of them have it.
+func presentPermissions(found []ssoSql.MssqlPermission, columns []string, deny bool) ([]string, bool) {
+	covered := map[string]int{}
+	withGrantOption := true
+
+	for _, p := range found {
+		if deny != (p.State == "D") {
+			continue
+		}
+
+		if (len(columns) == 0 && p.Column != "") || (len(columns) > 0 && !slices.Contains(columns, p.Column)) {
+			continue
+		}
+
+		covered[p.Permission]++
+		withGrantOption = withGrantOption && p.State == "W"
+	}
+
+	var permissions []string
+	for permission, count := range covered {
+		if count >= max(len(columns), 1) {
+			permissions = append(permissions, permission)
+		}
+	}
+
+	return permissions, withGrantOption && len(permissions) > 0
+}
+
+// apply grants or denies the permissions as configured by the state of the model.
+func (m mssqlPermissionModel) apply(ctx context.Context, conn permissionConnection, permissions []string, diags *diag.Diagnostics) {
+	if m.State.ValueString() == "deny" {
+		conn.Deny(ctx, diags, permissions)
+	} else {
+		conn.Grant(ctx, diags, permissions, m.WithGrantOption.ValueBool())
+	}
+}
+
+func (m *mssqlPermissionModel) create(ctx context.Context, securable mssqlSecurable, diags *diag.Diagnostics) {
+	var permissions []string
+	diags.Append(m.Permissions.ElementsAs(ctx, &permissions, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	conn := m.primaryConnection(ctx, securable, diags)
+	if diags.HasError() {
+		return
+	}
+
+	m.apply(ctx, conn, permissions, diags)
+
+	if diags.HasError() {
+		return
+	}
+
+	id := conn.Id()
+	m.ID = types.StringValue(id)
+}
+
+// update revokes the permissions which are no longer planned and applies the new ones.
+func (m mssqlPermissionModel) update(ctx context.Context, state mssqlPermissionModel, securable mssqlSecurable, diags *diag.Diagnostics) {
+	var plannedPermissions, currentPermissions []string
+	diags.Append(m.Permissions.ElementsAs(ctx, &plannedPermissions, false)...)
+	diags.Append(state.Permissions.ElementsAs(ctx, &currentPermissions, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	conn := m.primaryConnection(ctx, securable, diags)
+	if diags.HasError() {
+		return
+	}
+
+	revoke := utils.Difference(currentPermissions, plannedPermissions)
+	apply := utils.Difference(plannedPermissions, currentPermissions)
+
+	// A different state or grant option cannot be layered on top, everything is revoked and applied again
+	if !m.State.Equal(state.State) || !m.WithGrantOption.Equal(state.WithGrantOption) {
+		revoke, apply = currentPermissions, plannedPermissions
+	}
+
+	conn.Revoke(ctx, diags, revoke, state.WithGrantOption.ValueBool())
+
+	if diags.HasError() {
+		return
+	}
+
+	m.apply(ctx, conn, apply, diags)
+}
+
+func (m mssqlPermissionModel) delete(ctx context.Context, securable mssqlSecurable, diags *diag.Diagnostics) {
+	var permissions []string
+	diags.Append(m.Permissions.ElementsAs(ctx, &permissions, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	conn := m.primaryConnection(ctx, securable, diags)
+	if diags.HasError() {
+		return
+	}
+
+	conn.Revoke(ctx, diags, permissions, m.WithGrantOption.ValueBool())
+}
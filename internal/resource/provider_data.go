@@ -0,0 +1,37 @@
+package resource
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// ProviderData is the provider configuration handed to the resources.
+type ProviderData struct {
+	// MaintenanceDatabase is the Postgres database principals are created and dropped on, empty for postgres.
+	MaintenanceDatabase string
+}
+
+// configureProviderData returns the provider configuration, which is empty until the provider is configured.
+func configureProviderData(req resource.ConfigureRequest, resp *resource.ConfigureResponse) ProviderData {
+	if req.ProviderData == nil {
+		return ProviderData{}
+	}
+
+	data, ok := req.ProviderData.(ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("Expected ProviderData, got %T.", req.ProviderData))
+	}
+
+	return data
+}
+
+// maintenanceDatabase returns the maintenance database of the resource, falling back to the one of the provider.
+func (p ProviderData) maintenanceDatabase(override types.String) string {
+	if override.IsNull() || override.IsUnknown() {
+		return p.MaintenanceDatabase
+	}
+
+	return override.ValueString()
+}
@@ -0,0 +1,233 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &mssqlFirewallRuleResource{}
+	_ resource.ResourceWithImportState    = &mssqlFirewallRuleResource{}
+	_ resource.ResourceWithValidateConfig = &mssqlFirewallRuleResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlFirewallRule() resource.Resource {
+	return &mssqlFirewallRuleResource{}
+}
+
+type mssqlFirewallRuleResource struct {
+}
+
+type mssqlFirewallRuleResourceModel struct {
+	ID             types.String `tfsdk:"id"`
+	SqlServer      types.String `tfsdk:"sql_server_dns"`
+	Database       types.String `tfsdk:"database"`
+	Port           types.Int64  `tfsdk:"port"`
+	RuleName       types.String `tfsdk:"rule_name"`
+	StartIpAddress types.String `tfsdk:"start_ip_address"`
+	EndIpAddress   types.String `tfsdk:"end_ip_address"`
+}
+
+func (d *mssqlFirewallRuleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_database_firewall_rule"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlFirewallRuleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_database_firewall_rule` manages a database level IP firewall rule of an Azure SQL database. Unlike server level rules these can only be set through a connection to the database, which is made with the AAD identity terraform runs as. Database level firewall rules only exist on Azure SQL Database, so unlike the other MS SQL resources it has no `flavor`.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<rule_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database to add the rule.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			ruleNameProp: schema.StringAttribute{
+				Description: "The name of the firewall rule.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			startIpAddressProp: schema.StringAttribute{
+				Description: "The first IPv4 address of the allowed range.",
+				Required:    true,
+			},
+			endIpAddressProp: schema.StringAttribute{
+				Description: "The last IPv4 address of the allowed range, use the start address to allow a single address.",
+				Required:    true,
+			},
+		}}
+}
+
+func (d *mssqlFirewallRuleResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config mssqlFirewallRuleResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for name, value := range map[string]types.String{startIpAddressProp: config.StartIpAddress, endIpAddressProp: config.EndIpAddress} {
+		if value.IsNull() || value.IsUnknown() {
+			continue
+		}
+
+		if ip := net.ParseIP(value.ValueString()); ip == nil || ip.To4() == nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(name),
+				"Invalid IP address",
+				fmt.Sprintf("%q must be an IPv4 address, got %q.", name, value.ValueString()),
+			)
+		}
+	}
+}
+
+func (d *mssqlFirewallRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlFirewallRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlFirewallRule(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), ssoSql.AzureSqlDatabase), state.RuleName.ValueString(), state.StartIpAddress.ValueString(), state.EndIpAddress.ValueString())
+	rule, found := conn.ReadRule(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.StartIpAddress = types.StringValue(rule.StartIpAddress)
+	state.EndIpAddress = types.StringValue(rule.EndIpAddress)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlFirewallRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlFirewallRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlFirewallRule(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), ssoSql.AzureSqlDatabase), plan.RuleName.ValueString(), plan.StartIpAddress.ValueString(), plan.EndIpAddress.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if _, found := conn.ReadRule(ctx, &resp.Diagnostics); found {
+		resp.Diagnostics.AddError(
+			"Firewall rule already exists",
+			fmt.Sprintf("The database already has a firewall rule named %q, import it instead.", plan.RuleName.ValueString()),
+		)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.SetRule(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlFirewallRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan mssqlFirewallRuleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlFirewallRule(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), ssoSql.AzureSqlDatabase), plan.RuleName.ValueString(), plan.StartIpAddress.ValueString(), plan.EndIpAddress.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.SetRule(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlFirewallRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlFirewallRuleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlFirewallRule(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), ssoSql.AzureSqlDatabase), state.RuleName.ValueString(), state.StartIpAddress.ValueString(), state.EndIpAddress.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.DeleteRule(ctx, &resp.Diagnostics)
+}
+
+func (d *mssqlFirewallRuleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, ruleName, err := ssoSql.ParseMssqlId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(ruleNameProp), ruleName)...)
+}
@@ -0,0 +1,59 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqDatabaseFirewallRule(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Database Firewall Rule")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+
+	expectedId := fmt.Sprint(serverDns, ":", dbName, ":1433", "/", "tf_acc_rule")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqDatabaseFirewallRule, serverDns, dbName, "192.0.2.1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_firewall_rule.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_firewall_rule.example", "end_ip_address", "192.0.2.1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqDatabaseFirewallRule, serverDns, dbName, "192.0.2.255"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_firewall_rule.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_firewall_rule.example", "end_ip_address", "192.0.2.255"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_database_firewall_rule.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqDatabaseFirewallRule = `
+resource "sqlsso_mssql_database_firewall_rule" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	rule_name = "tf_acc_rule"
+	start_ip_address = "192.0.2.1"
+	end_ip_address = "%[3]s"
+}
+`
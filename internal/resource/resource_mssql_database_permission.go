@@ -0,0 +1,185 @@
+package resource
+
+import (
+	"context"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &mssqlDatabasePermissionResource{}
+	_ resource.ResourceWithImportState    = &mssqlDatabasePermissionResource{}
+	_ resource.ResourceWithValidateConfig = &mssqlDatabasePermissionResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlDatabasePermission() resource.Resource {
+	return &mssqlDatabasePermissionResource{}
+}
+
+type mssqlDatabasePermissionResource struct {
+}
+
+type mssqlDatabasePermissionResourceModel struct {
+	mssqlPermissionModel
+}
+
+// databaseSecurable is the database itself, database permissions have no securable name or columns.
+var databaseSecurable = mssqlSecurable{class: "DATABASE"}
+
+func (d *mssqlDatabasePermissionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_database_permission"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlDatabasePermissionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_database_permission` grants or denies database scoped permissions such as `VIEW DATABASE STATE`, `EXECUTE`, `UNMASK` or `SHOWPLAN` to a user or role in an Azure MS SQL database. Permissions added or removed outside of terraform are detected and changed in place.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<principal_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database to grant the permissions in.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute(""),
+			principalNameProp: schema.StringAttribute{
+				Description: "The user or role receiving the permissions.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			permissionsProp: schema.SetAttribute{
+				Description: "The database permissions to grant or deny (e.g. `VIEW DATABASE STATE`, `EXECUTE`, `UNMASK`, `SHOWPLAN`).",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.Set{
+					permissionsValidator{},
+				},
+			},
+			permissionStateProp: permissionStateAttribute(),
+			withGrantOptionProp: withGrantOptionAttribute(),
+		}}
+}
+
+func (d *mssqlDatabasePermissionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config mssqlDatabasePermissionResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	config.validate(&resp.Diagnostics)
+}
+
+func (d *mssqlDatabasePermissionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlDatabasePermissionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found := state.read(ctx, databaseSecurable, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlDatabasePermissionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlDatabasePermissionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.create(ctx, databaseSecurable, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlDatabasePermissionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state mssqlDatabasePermissionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.update(ctx, state.mssqlPermissionModel, databaseSecurable, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlDatabasePermissionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlDatabasePermissionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.delete(ctx, databaseSecurable, &resp.Diagnostics)
+}
+
+func (d *mssqlDatabasePermissionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, principal, err := ssoSql.ParseMssqlId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(principalNameProp), principal)...)
+}
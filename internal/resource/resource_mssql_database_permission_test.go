@@ -0,0 +1,66 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqDatabasePermission(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Database Permission")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+
+	expectedId := fmt.Sprint(serverDns, ":", dbName, ":1433", "/", "tf_acc_db_permission_role")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqDatabasePermission, serverDns, dbName, `"VIEW DATABASE STATE"`, "false"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_permission.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_permission.example", "permissions.#", "1"),
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_permission.example", "state", "grant"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqDatabasePermission, serverDns, dbName, `"VIEW DATABASE STATE", "SHOWPLAN"`, "true"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_permission.example", "permissions.#", "2"),
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_permission.example", "with_grant_option", "true"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_database_permission.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqDatabasePermission = `
+resource "sqlsso_mssql_database_role" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "tf_acc_db_permission_role"
+}
+
+resource "sqlsso_mssql_database_permission" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	principal_name = sqlsso_mssql_database_role.example.role_name
+	permissions = [%[3]s]
+	with_grant_option = %[4]s
+}
+`
@@ -0,0 +1,245 @@
+package resource
+
+import (
+	"context"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+	"terraform-provider-sqlsso/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &mssqlRoleResource{}
+	_ resource.ResourceWithImportState = &mssqlRoleResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlRole() resource.Resource {
+	return &mssqlRoleResource{}
+}
+
+type mssqlRoleResource struct {
+}
+
+type mssqlRoleResourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	SqlServer types.String `tfsdk:"sql_server_dns"`
+	Database  types.String `tfsdk:"database"`
+	Port      types.Int64  `tfsdk:"port"`
+	Flavor    types.String `tfsdk:"flavor"`
+	RoleName  types.String `tfsdk:"role_name"`
+	Owner     types.String `tfsdk:"owner"`
+	MemberOf  types.Set    `tfsdk:"member_of"`
+}
+
+func (d *mssqlRoleResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_database_role"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlRoleResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_database_role` creates a custom database role in an Azure MS SQL database which can be given to accounts managed by `sqlsso_mssql_server_aad_account`.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<role_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server to add the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database to add the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute("Synapse SQL pools change role membership with `sp_addrolemember`."),
+			roleNameProp: schema.StringAttribute{
+				Description: "The name of the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			ownerProp: schema.StringAttribute{
+				Description: "The user or role owning the role.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("dbo"),
+			},
+			memberOfProp: schema.SetAttribute{
+				Description: "Roles this role should be a member of (e.g. `db_datareader`).",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		}}
+}
+
+func (d *mssqlRoleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRole(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.RoleName.ValueString(), state.Owner.ValueString())
+	role, found := conn.ReadRole(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Flavor = mssqlFlavorOrDefault(state.Flavor)
+
+	state.Owner = types.StringValue(role.Owner)
+
+	if !state.MemberOf.IsNull() || len(role.MemberOf) > 0 {
+		memberOf, diags := types.SetValueFrom(ctx, types.StringType, role.MemberOf)
+		resp.Diagnostics.Append(diags...)
+		state.MemberOf = memberOf
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlRoleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var memberOf []string
+	resp.Diagnostics.Append(plan.MemberOf.ElementsAs(ctx, &memberOf, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRole(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.RoleName.ValueString(), plan.Owner.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.CreateRole(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.AddMemberOf(ctx, &resp.Diagnostics, memberOf)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlRoleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state mssqlRoleResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plannedMemberOf, currentMemberOf []string
+	resp.Diagnostics.Append(plan.MemberOf.ElementsAs(ctx, &plannedMemberOf, false)...)
+	resp.Diagnostics.Append(state.MemberOf.ElementsAs(ctx, &currentMemberOf, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRole(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.RoleName.ValueString(), plan.Owner.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Owner.Equal(state.Owner) {
+		conn.SetOwner(ctx, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.DropMemberOf(ctx, &resp.Diagnostics, utils.Difference(currentMemberOf, plannedMemberOf))
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.AddMemberOf(ctx, &resp.Diagnostics, utils.Difference(plannedMemberOf, currentMemberOf))
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlRoleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlRoleResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRole(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.RoleName.ValueString(), state.Owner.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.DropRole(ctx, &resp.Diagnostics)
+}
+
+func (d *mssqlRoleResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, role, err := ssoSql.ParseMssqlId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(roleNameProp), role)...)
+}
@@ -0,0 +1,52 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqDatabaseRole(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Database Role")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+
+	config := fmt.Sprintf(testAccresourceMsSlqDatabaseRole, serverDns, dbName)
+	expectedId := fmt.Sprint(serverDns, ":", dbName, ":1433", "/", "tf_acc_reporting")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_role.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_database_role.example", "owner", "dbo"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_database_role.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqDatabaseRole = `
+resource "sqlsso_mssql_database_role" "example" {
+  sql_server_dns = "%s"
+	database = "%s"
+	role_name = "tf_acc_reporting"
+	member_of = ["db_datareader"]
+}
+`
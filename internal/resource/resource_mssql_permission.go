@@ -0,0 +1,262 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &mssqlPermissionResource{}
+	_ resource.ResourceWithImportState    = &mssqlPermissionResource{}
+	_ resource.ResourceWithValidateConfig = &mssqlPermissionResource{}
+)
+
+var mssqlSecurableClassMap = map[string]string{"schema": "SCHEMA", "object": "OBJECT"}
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlPermission() resource.Resource {
+	return &mssqlPermissionResource{}
+}
+
+type mssqlPermissionResource struct {
+}
+
+type mssqlPermissionResourceModel struct {
+	mssqlPermissionModel
+	SecurableClass types.String `tfsdk:"securable_class"`
+	SecurableName  types.String `tfsdk:"securable_name"`
+	Columns        types.Set    `tfsdk:"columns"`
+}
+
+func (m mssqlPermissionResourceModel) securable(ctx context.Context, diags *diag.Diagnostics) mssqlSecurable {
+	var columns []string
+	diags.Append(m.Columns.ElementsAs(ctx, &columns, false)...)
+
+	return mssqlSecurable{
+		class:   mssqlSecurableClassMap[m.SecurableClass.ValueString()],
+		name:    m.SecurableName.ValueString(),
+		columns: columns,
+	}
+}
+
+func (d *mssqlPermissionResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_permission"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlPermissionResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_permission` grants or denies permissions on a schema, an object or columns of an object to a user or role in an Azure MS SQL database. Permissions added or removed outside of terraform are detected and changed in place.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<principal_name>/<SCHEMA|OBJECT>::<securable_name>`, where a `/` or `%` in the principal or securable name is written as `%2F` or `%25`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database containing the securable.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute(""),
+			principalNameProp: schema.StringAttribute{
+				Description: "The user or role receiving the permissions.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			securableClassProp: schema.StringAttribute{
+				Description: "The kind of securable: `schema` or `object` (tables, views, procedures and functions).",
+				Required:    true,
+				Validators: []validator.String{
+					stringInMap(mssqlSecurableClassMap),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			securableNameProp: schema.StringAttribute{
+				Description: "The name of the schema, or the name of the object optionally prefixed with its schema (e.g. `sales.orders`).",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			columnsProp: schema.SetAttribute{
+				Description: "Limits the permissions to these columns of the object, e.g. for column level `SELECT` or `UPDATE`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Set{
+					setplanmodifier.RequiresReplace(),
+				},
+			},
+			permissionsProp: schema.SetAttribute{
+				Description: "The permissions to grant or deny (e.g. `SELECT`, `EXECUTE`, `VIEW DEFINITION`).",
+				ElementType: types.StringType,
+				Required:    true,
+				Validators: []validator.Set{
+					permissionsValidator{},
+				},
+			},
+			permissionStateProp: permissionStateAttribute(),
+			withGrantOptionProp: withGrantOptionAttribute(),
+		}}
+}
+
+func (d *mssqlPermissionResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config mssqlPermissionResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Columns.IsNull() && !config.SecurableClass.IsUnknown() && config.SecurableClass.ValueString() != "object" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(columnsProp),
+			"Unexpected columns",
+			fmt.Sprintf("%q can only be set when %q is %q.", columnsProp, securableClassProp, "object"),
+		)
+	}
+
+	config.validate(&resp.Diagnostics)
+}
+
+func (d *mssqlPermissionResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlPermissionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	securable := state.securable(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found := state.read(ctx, securable, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlPermissionResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlPermissionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	securable := plan.securable(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.create(ctx, securable, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlPermissionResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state mssqlPermissionResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	securable := plan.securable(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.update(ctx, state.mssqlPermissionModel, securable, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlPermissionResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlPermissionResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	securable := state.securable(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.delete(ctx, securable, &resp.Diagnostics)
+}
+
+func (d *mssqlPermissionResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, principal, securableClass, securable, err := ssoSql.ParseMssqlPermissionId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	if _, ok := mssqlSecurableClassMap[strings.ToLower(securableClass)]; !ok {
+		resp.Diagnostics.AddError("Invalid import ID", fmt.Sprintf("unsupported securable class %q, expected SCHEMA or OBJECT", securableClass))
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(principalNameProp), principal)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(securableClassProp), strings.ToLower(securableClass))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(securableNameProp), securable)...)
+}
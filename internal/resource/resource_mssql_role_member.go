@@ -0,0 +1,186 @@
+package resource
+
+import (
+	"context"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &mssqlRoleMemberResource{}
+	_ resource.ResourceWithImportState = &mssqlRoleMemberResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlRoleMember() resource.Resource {
+	return &mssqlRoleMemberResource{}
+}
+
+type mssqlRoleMemberResource struct {
+}
+
+type mssqlRoleMemberResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	SqlServer  types.String `tfsdk:"sql_server_dns"`
+	Database   types.String `tfsdk:"database"`
+	Port       types.Int64  `tfsdk:"port"`
+	Flavor     types.String `tfsdk:"flavor"`
+	RoleName   types.String `tfsdk:"role_name"`
+	MemberName types.String `tfsdk:"member_name"`
+}
+
+func (d *mssqlRoleMemberResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_role_member"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlRoleMemberResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_role_member` adds an existing user or role to a database role in an Azure MS SQL database, e.g. a user created outside of terraform or a role nested in another role.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<role_name>/<member_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database containing the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute("Synapse SQL pools change role membership with `sp_addrolemember`."),
+			roleNameProp: schema.StringAttribute{
+				Description: "The name of the role (e.g. `db_datareader` or a custom role).",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			memberNameProp: schema.StringAttribute{
+				Description: "The name of the user or role to add to the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		}}
+}
+
+func (d *mssqlRoleMemberResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlRoleMemberResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRoleMember(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.RoleName.ValueString(), state.MemberName.ValueString())
+	found := conn.ReadMember(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Flavor = mssqlFlavorOrDefault(state.Flavor)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlRoleMemberResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlRoleMemberResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRoleMember(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.RoleName.ValueString(), plan.MemberName.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.AddMember(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlRoleMemberResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	// Only attributes which do not touch the database (e.g. flavor) can change in place
+	var plan mssqlRoleMemberResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlRoleMemberResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlRoleMemberResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRoleMember(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.RoleName.ValueString(), state.MemberName.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.DropMember(ctx, &resp.Diagnostics)
+}
+
+func (d *mssqlRoleMemberResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, role, member, err := ssoSql.ParseMssqlRoleMemberId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(roleNameProp), role)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(memberNameProp), member)...)
+}
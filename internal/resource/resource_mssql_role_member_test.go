@@ -0,0 +1,57 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqRoleMember(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Role Member")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+
+	config := fmt.Sprintf(testAccresourceMsSlqRoleMember, serverDns, dbName)
+	expectedId := fmt.Sprint(serverDns, ":", dbName, ":1433", "/", "db_datareader/tf_acc_member")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_role_member.example", "id", expectedId),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_role_member.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqRoleMember = `
+resource "sqlsso_mssql_database_role" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "tf_acc_member"
+}
+
+resource "sqlsso_mssql_role_member" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "db_datareader"
+	member_name = sqlsso_mssql_database_role.example.role_name
+}
+`
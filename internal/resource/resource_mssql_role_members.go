@@ -0,0 +1,272 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+	"terraform-provider-sqlsso/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &mssqlRoleMembersResource{}
+	_ resource.ResourceWithImportState    = &mssqlRoleMembersResource{}
+	_ resource.ResourceWithValidateConfig = &mssqlRoleMembersResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlRoleMembers() resource.Resource {
+	return &mssqlRoleMembersResource{}
+}
+
+type mssqlRoleMembersResource struct {
+}
+
+type mssqlRoleMembersResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	SqlServer     types.String `tfsdk:"sql_server_dns"`
+	Database      types.String `tfsdk:"database"`
+	Port          types.Int64  `tfsdk:"port"`
+	Flavor        types.String `tfsdk:"flavor"`
+	RoleName      types.String `tfsdk:"role_name"`
+	Members       types.Set    `tfsdk:"members"`
+	IgnoreMembers types.Set    `tfsdk:"ignore_members"`
+}
+
+func (d *mssqlRoleMembersResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_role_members"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlRoleMembersResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_role_members` authoritatively manages the members of a database role in an Azure MS SQL database: members added outside of terraform are removed. Use `sqlsso_mssql_role_member` to add single members instead, the two should not be used for the same role. `dbo` is never listed and cannot be declared as it cannot be removed from `db_owner`.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<role_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database containing the role.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute("Synapse SQL pools change role membership with `sp_addrolemember`."),
+			roleNameProp: schema.StringAttribute{
+				Description: "The name of the role (e.g. `db_owner`).",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			membersProp:       membersAttribute(),
+			ignoreMembersProp: ignoreMembersAttribute(),
+		}}
+}
+
+func (d *mssqlRoleMembersResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config mssqlRoleMembersResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateMembers(ctx, config.Members, config.IgnoreMembers, &resp.Diagnostics)
+
+	// dbo is never listed, so declaring it would show a change on every plan
+	var declared []types.String
+	resp.Diagnostics.Append(config.Members.ElementsAs(ctx, &declared, true)...)
+	if slices.Contains(declared, types.StringValue("dbo")) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(membersProp),
+			"Unsupported member",
+			fmt.Sprintf("%q is always a member of %q and cannot be managed, remove it from %q.", "dbo", "db_owner", membersProp),
+		)
+	}
+}
+
+func (d *mssqlRoleMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlRoleMembersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var ignored []string
+	resp.Diagnostics.Append(state.IgnoreMembers.ElementsAs(ctx, &ignored, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRole(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.RoleName.ValueString(), "")
+	_, found := conn.ReadRole(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	members := conn.ListMembers(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	memberSet, diags := types.SetValueFrom(ctx, types.StringType, managedMembers(members, ignored))
+	resp.Diagnostics.Append(diags...)
+	state.Members = memberSet
+
+	state.Flavor = mssqlFlavorOrDefault(state.Flavor)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// reconcile adds the planned members and removes everyone else who is not ignored.
+func (m mssqlRoleMembersResourceModel) reconcile(ctx context.Context, diags *diag.Diagnostics) {
+	var planned, ignored []string
+	diags.Append(m.Members.ElementsAs(ctx, &planned, false)...)
+	diags.Append(m.IgnoreMembers.ElementsAs(ctx, &ignored, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	server := ssoSql.CreateMssqlServer(m.SqlServer.ValueString(), m.Database.ValueString(), m.Port.ValueInt64(), mssqlFlavorMap[m.Flavor.ValueString()])
+	server.UsePrimary(ctx, diags)
+	if diags.HasError() {
+		return
+	}
+
+	current := managedMembers(ssoSql.CreateMssqlRole(server, m.RoleName.ValueString(), "").ListMembers(ctx, diags), ignored)
+	if diags.HasError() {
+		return
+	}
+
+	for _, member := range utils.Difference(current, planned) {
+		ssoSql.CreateMssqlRoleMember(server, m.RoleName.ValueString(), member).DropMember(ctx, diags)
+
+		if diags.HasError() {
+			return
+		}
+	}
+
+	for _, member := range utils.Difference(planned, current) {
+		ssoSql.CreateMssqlRoleMember(server, m.RoleName.ValueString(), member).AddMember(ctx, diags)
+
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+func (d *mssqlRoleMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlRoleMembersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.reconcile(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlRole(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.RoleName.ValueString(), "")
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlRoleMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan mssqlRoleMembersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.reconcile(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlRoleMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlRoleMembersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var members []string
+	resp.Diagnostics.Append(state.Members.ElementsAs(ctx, &members, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the declared members are removed, the role itself and ignored members stay
+	server := ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()])
+	server.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, member := range members {
+		ssoSql.CreateMssqlRoleMember(server, state.RoleName.ValueString(), member).DropMember(ctx, &resp.Diagnostics)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+}
+
+func (d *mssqlRoleMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, roleName, err := ssoSql.ParseMssqlId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(roleNameProp), roleName)...)
+}
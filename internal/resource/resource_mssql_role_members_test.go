@@ -0,0 +1,75 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqRoleMembers(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Role Members")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+
+	expectedId := fmt.Sprint(serverDns, ":", dbName, ":1433", "/", "tf_acc_members")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqRoleMembers, serverDns, dbName, "sqlsso_mssql_database_role.first.role_name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_role_members.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_role_members.example", "members.#", "1"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqRoleMembers, serverDns, dbName, "sqlsso_mssql_database_role.first.role_name, sqlsso_mssql_database_role.second.role_name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_role_members.example", "members.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_role_members.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqRoleMembers = `
+resource "sqlsso_mssql_database_role" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "tf_acc_members"
+}
+
+resource "sqlsso_mssql_database_role" "first" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "tf_acc_members_first"
+}
+
+resource "sqlsso_mssql_database_role" "second" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "tf_acc_members_second"
+}
+
+resource "sqlsso_mssql_role_members" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = sqlsso_mssql_database_role.example.role_name
+	members = [%[3]s]
+}
+`
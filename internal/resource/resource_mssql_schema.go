@@ -0,0 +1,255 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &mssqlSchemaResource{}
+	_ resource.ResourceWithImportState = &mssqlSchemaResource{}
+)
+
+var mssqlDestroyObjectsMap = map[string]struct{}{"fail": {}, "drop": {}, "transfer": {}}
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlSchema() resource.Resource {
+	return &mssqlSchemaResource{}
+}
+
+type mssqlSchemaResource struct {
+}
+
+type mssqlSchemaResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	SqlServer         types.String `tfsdk:"sql_server_dns"`
+	Database          types.String `tfsdk:"database"`
+	Port              types.Int64  `tfsdk:"port"`
+	Flavor            types.String `tfsdk:"flavor"`
+	SchemaName        types.String `tfsdk:"schema_name"`
+	Owner             types.String `tfsdk:"owner"`
+	OnDestroyObjects  types.String `tfsdk:"on_destroy_objects"`
+	TransferObjectsTo types.String `tfsdk:"transfer_objects_to"`
+}
+
+func (d *mssqlSchemaResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_schema"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlSchemaResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_schema` creates a schema in an Azure MS SQL database owned by an AAD user or role.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<schema_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server to add the schema.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			databaseProp: schema.StringAttribute{
+				Description: "The name of the database to add the schema.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute(""),
+			schemaNameProp: schema.StringAttribute{
+				Description: "The name of the schema.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			ownerProp: schema.StringAttribute{
+				Description: "The user or role owning the schema (e.g. an account managed by `sqlsso_mssql_server_aad_account`).",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("dbo"),
+			},
+			onDestroyObjectsProp: schema.StringAttribute{
+				Description: "What to do on destroy when the schema still contains objects: `fail` returns an error listing the objects, `drop` drops them and `transfer` moves them to `transfer_objects_to`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("fail"),
+				Validators: []validator.String{
+					stringInMap(mssqlDestroyObjectsMap),
+				},
+			},
+			transferObjectsToProp: schema.StringAttribute{
+				Description: "The schema which receives the objects when `on_destroy_objects` is `transfer`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("dbo"),
+			},
+		}}
+}
+
+func (d *mssqlSchemaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlSchemaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlSchema(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.SchemaName.ValueString(), state.Owner.ValueString())
+	dbSchema, found := conn.ReadSchema(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Owner = types.StringValue(dbSchema.Owner)
+	state.Flavor = mssqlFlavorOrDefault(state.Flavor)
+
+	// Imported schemas have no destroy settings yet
+	if state.OnDestroyObjects.IsNull() {
+		state.OnDestroyObjects = types.StringValue("fail")
+	}
+	if state.TransferObjectsTo.IsNull() {
+		state.TransferObjectsTo = types.StringValue("dbo")
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlSchemaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlSchemaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlSchema(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.SchemaName.ValueString(), plan.Owner.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.CreateSchema(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlSchemaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state mssqlSchemaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Owner.Equal(state.Owner) {
+		conn := ssoSql.CreateMssqlSchema(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.SchemaName.ValueString(), plan.Owner.ValueString())
+		conn.UsePrimary(ctx, &resp.Diagnostics)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		conn.SetOwner(ctx, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlSchemaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlSchemaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlSchema(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.SchemaName.ValueString(), state.Owner.ValueString())
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch state.OnDestroyObjects.ValueString() {
+	case "drop":
+		conn.DropObjects(ctx, &resp.Diagnostics)
+	case "transfer":
+		conn.TransferObjects(ctx, &resp.Diagnostics, state.TransferObjectsTo.ValueString())
+	default:
+		objects := conn.ListObjects(ctx, &resp.Diagnostics)
+
+		if len(objects) > 0 {
+			var list strings.Builder
+			for _, o := range objects {
+				list.WriteString(fmt.Sprintf("\n  - %s", o))
+			}
+
+			resp.Diagnostics.AddError(
+				"Schema not empty",
+				fmt.Sprintf("The schema %q cannot be dropped because it contains:%s\n\nRemove the objects first or set %q to %q or %q.", state.SchemaName.ValueString(), list.String(), onDestroyObjectsProp, "drop", "transfer"),
+			)
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.DropSchema(ctx, &resp.Diagnostics)
+}
+
+func (d *mssqlSchemaResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, schemaName, err := ssoSql.ParseMssqlId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(schemaNameProp), schemaName)...)
+}
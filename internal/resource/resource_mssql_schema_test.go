@@ -0,0 +1,63 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqSchema(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Schema")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+
+	expectedId := fmt.Sprint(serverDns, ":", dbName, ":1433", "/", "tf_acc_schema")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqSchema, serverDns, dbName, `"dbo"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_schema.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_schema.example", "owner", "dbo"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(testAccresourceMsSlqSchema, serverDns, dbName, "sqlsso_mssql_database_role.example.role_name"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_schema.example", "owner", "tf_acc_schema_owner"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_schema.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqSchema = `
+resource "sqlsso_mssql_database_role" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	role_name = "tf_acc_schema_owner"
+}
+
+resource "sqlsso_mssql_schema" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	schema_name = "tf_acc_schema"
+	owner = %[3]s
+}
+`
@@ -3,9 +3,14 @@ package resource
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	ssoSql "terraform-provider-sqlsso/internal/sql"
+	"terraform-provider-sqlsso/internal/utils"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
@@ -19,11 +24,14 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &mssqlResource{}
+	_ resource.Resource                   = &mssqlResource{}
+	_ resource.ResourceWithValidateConfig = &mssqlResource{}
 )
 
 var accountTypeMap = map[string]string{"user": "E", "group": "X"}
+var mssqlPrincipalKindMap = map[string]string{"user": "E", "group": "X", "service_principal": "E", "managed_identity": "E"}
 var mssqlRoleMap = map[string]string{"owner": "db_owner", "reader": "db_datareader", "writer": "db_datawriter"}
+var mssqlCreationModeMap = map[string]ssoSql.MssqlCreationMode{"sid": ssoSql.CreateWithSid, "external_provider": ssoSql.CreateFromExternalProvider, "object_id": ssoSql.CreateWithObjectId}
 
 // New is a helper function to simplify the provider implementation.
 func NewMssql() resource.Resource {
@@ -34,14 +42,24 @@ type mssqlResource struct {
 }
 
 type mssqlResourceModel struct {
-	ID          types.String `tfsdk:"id"`
-	SqlServer   types.String `tfsdk:"sql_server_dns"`
-	Database    types.String `tfsdk:"database"`
-	Account     types.String `tfsdk:"account_name"`
-	Port        types.Int64  `tfsdk:"port"`
-	ObjectId    types.String `tfsdk:"object_id"`
-	AccountType types.String `tfsdk:"account_type"`
-	Role        types.String `tfsdk:"role"`
+	ID                 types.String `tfsdk:"id"`
+	SqlServer          types.String `tfsdk:"sql_server_dns"`
+	Database           types.String `tfsdk:"database"`
+	Account            types.String `tfsdk:"account_name"`
+	Port               types.Int64  `tfsdk:"port"`
+	Flavor             types.String `tfsdk:"flavor"`
+	ObjectId           types.String `tfsdk:"object_id"`
+	AccountType        types.String `tfsdk:"account_type"`
+	Role               types.String `tfsdk:"role"`
+	CreationMode       types.String `tfsdk:"creation_mode"`
+	PrincipalKind      types.String `tfsdk:"principal_kind"`
+	ClientId           types.String `tfsdk:"client_id"`
+	IfExists           types.String `tfsdk:"if_exists"`
+	OnDestroyOwnership types.String `tfsdk:"on_destroy_ownership"`
+	ReassignOwnedTo    types.String `tfsdk:"reassign_owned_to"`
+	DeleteBehavior     types.String `tfsdk:"delete_behavior"`
+	VerifyReplicas     types.Set    `tfsdk:"verify_replicas"`
+	ReplicationTimeout types.String `tfsdk:"replication_timeout"`
 }
 
 func (d *mssqlResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
@@ -51,7 +69,7 @@ func (d *mssqlResource) Metadata(_ context.Context, req resource.MetadataRequest
 // Schema defines the schema for the resource.
 func (d *mssqlResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "`sqlsso_mssql_server_aad_account` enables AAD authentication for an Azure MS SQL server.\n\nFor this to work terraform should be run for the configured **Active Directory Admin** account, not the SQL Server Admin as AD users can only be administered with the AD Admin account. ",
+		Description: "`sqlsso_mssql_server_aad_account` enables AAD authentication for an Azure MS SQL server.\n\nFor this to work terraform should be run for the configured **Active Directory Admin** account, not the SQL Server Admin as AD users can only be administered with the AD Admin account. \n\nGeo-replicated databases can be managed through the read-write listener of a failover group. When the configured database is a read-only secondary, changes are made on its current primary instead while refreshes keep reading the configured server, so list it in `verify_replicas` to wait for the account to show up there.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -60,7 +78,7 @@ func (d *mssqlResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 			sqlServerDnsProp: schema.StringAttribute{
-				Description: "The DNS name of the SQL server to add the account.",
+				Description: "The DNS name of the SQL server to add the account, or the read-write listener of a failover group.",
 				Required:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
@@ -89,18 +107,37 @@ func (d *mssqlResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 					int64planmodifier.RequiresReplace(),
 				},
 			},
+			flavorProp: mssqlFlavorAttribute("Synapse SQL pools change role membership with `sp_addrolemember` and only support creating users with `creation_mode` `external_provider`."),
 			objectIdProp: schema.StringAttribute{
-				Description: "Azure AD object ID for the account.",
-				Required:    true,
+				Description: "Azure AD object ID for the account. Required when `creation_mode` is `sid` or `object_id`.",
+				Optional:    true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
-			accountTypeProp: schema.StringAttribute{
-				Description: "Type of account to create: either a single user or an AAD group.",
+			clientIdProp: schema.StringAttribute{
+				Description: "Application (client) ID of the service principal or managed identity. Required when `principal_kind` is `service_principal` or `managed_identity` and `creation_mode` is `sid`.",
 				Optional:    true,
-				Computed:    true,
-				Default:     stringdefault.StaticString("user"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			principalKindProp: schema.StringAttribute{
+				Description: "Kind of Azure AD principal: `user`, `group`, `service_principal` or `managed_identity`. Determines whether the SID is computed from `object_id` or `client_id` and which type the user is created with.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringInMap(mssqlPrincipalKindMap),
+				},
+			},
+			accountTypeProp: schema.StringAttribute{
+				Description:        "Type of account to create: either a single user or an AAD group. Only used when `creation_mode` is `sid`.",
+				DeprecationMessage: "Use principal_kind instead.",
+				Optional:           true,
+				Computed:           true,
+				Default:            stringdefault.StaticString("user"),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
@@ -109,20 +146,183 @@ func (d *mssqlResource) Schema(_ context.Context, _ resource.SchemaRequest, resp
 				},
 			},
 			roleProp: schema.StringAttribute{
-				Description: "The role the account should get (e.g. owner, reader, etc.).",
+				Description: "The role the account should get: `owner`, `reader`, `writer` or the name of a database role (e.g. one managed by `sqlsso_mssql_database_role`).",
 				Optional:    true,
 				Computed:    true,
 				Default:     stringdefault.StaticString("reader"),
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.RequiresReplace(),
 				},
+			},
+			creationModeProp: schema.StringAttribute{
+				Description: "How the user is created: `sid` computes the SID from `object_id`, `external_provider` lets the server look the account name up in Azure AD and `object_id` uses `FROM EXTERNAL PROVIDER WITH OBJECT_ID`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("sid"),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 				Validators: []validator.String{
-					stringInMap(mssqlRoleMap),
+					stringInMap(mssqlCreationModeMap),
 				},
 			},
+			ifExistsProp: ifExistsAttribute(),
+			onDestroyOwnershipProp: schema.StringAttribute{
+				Description: "What to do on destroy when the account owns schemas, objects or roles: `fail` returns an error listing what is owned and `reassign` transfers ownership to `reassign_owned_to` before dropping the account.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("fail"),
+				Validators: []validator.String{
+					stringInMap(destroyOwnershipMap),
+				},
+			},
+			reassignOwnedToProp: schema.StringAttribute{
+				Description: "The principal which takes over ownership when `on_destroy_ownership` is `reassign`.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("dbo"),
+			},
+			deleteBehaviorProp: deleteBehaviorAttribute("revokes `CONNECT`"),
+			verifyReplicasProp: schema.SetAttribute{
+				Description: "DNS names of geo-replication secondaries (e.g. the other server of a failover group). After the account is created, terraform waits until it has replicated to each of them.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			replicationTimeoutProp: schema.StringAttribute{
+				Description: "How long to wait for the account to show up on `verify_replicas`, as a duration (e.g. `90s`, `5m`).",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("5m"),
+			},
 		}}
 }
 
+func (d *mssqlResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config mssqlResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.CreationMode.IsUnknown() || config.PrincipalKind.IsUnknown() {
+		return
+	}
+
+	creationMode := utils.ValueStringOrDefault(config.CreationMode, "sid")
+	principalKind := utils.ValueStringOrDefault(config.PrincipalKind, "")
+
+	if !config.ReplicationTimeout.IsNull() && !config.ReplicationTimeout.IsUnknown() {
+		if _, err := time.ParseDuration(config.ReplicationTimeout.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(replicationTimeoutProp),
+				"Invalid replication timeout",
+				fmt.Sprintf("%q must be a duration such as %q: %s", replicationTimeoutProp, "5m", err),
+			)
+		}
+	}
+
+	if !config.Flavor.IsUnknown() && isSynapseFlavor(config.Flavor.ValueString()) && creationMode != "external_provider" {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(creationModeProp),
+			"Unsupported creation mode",
+			fmt.Sprintf("Synapse SQL pools can only create users from the external provider, set %q to %q.", creationModeProp, "external_provider"),
+		)
+	}
+
+	if !config.PrincipalKind.IsNull() && !config.AccountType.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(accountTypeProp),
+			"Conflicting account type",
+			fmt.Sprintf("%q cannot be combined with %q, the type is derived from the principal kind.", accountTypeProp, principalKindProp),
+		)
+	}
+
+	if creationMode != "sid" && !config.AccountType.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(accountTypeProp),
+			"Unused account type",
+			fmt.Sprintf("%q is only used when %q is %q, otherwise the type is resolved by the server.", accountTypeProp, creationModeProp, "sid"),
+		)
+	}
+
+	// The id the user is created from: none when the server looks the name up, the client ID when
+	// the SID of an application is computed and the object ID otherwise.
+	requiredId := objectIdProp
+	reason := fmt.Sprintf("%q is %q", creationModeProp, creationMode)
+
+	switch {
+	case creationMode == "external_provider":
+		requiredId = ""
+	case creationMode == "sid" && mssqlPrincipalUsesClientId(principalKind):
+		requiredId = clientIdProp
+		reason = fmt.Sprintf("the SID of a %s is computed from its client ID", principalKind)
+	}
+
+	for _, attr := range []struct {
+		name  string
+		value types.String
+	}{{objectIdProp, config.ObjectId}, {clientIdProp, config.ClientId}} {
+		if attr.name == requiredId && attr.value.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Missing principal ID",
+				fmt.Sprintf("%q must be set when %s.", attr.name, reason),
+			)
+		}
+
+		if attr.name != requiredId && !attr.value.IsNull() && !attr.value.IsUnknown() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(attr.name),
+				"Unused principal ID",
+				fmt.Sprintf("%q is not used for a %q principal when %q is %q.", attr.name, utils.ValueStringOrDefault(config.PrincipalKind, "user"), creationModeProp, creationMode),
+			)
+		}
+	}
+}
+
+// mssqlRole maps the built-in role names to the fixed database roles, any other name is a custom database role.
+func mssqlRole(role string) string {
+	if fixedRole, ok := mssqlRoleMap[role]; ok {
+		return fixedRole
+	}
+
+	return role
+}
+
+// mssqlPrincipalUsesClientId reports whether the SID of the principal kind is its application (client) ID.
+func mssqlPrincipalUsesClientId(principalKind string) bool {
+	return principalKind == "service_principal" || principalKind == "managed_identity"
+}
+
+// principal returns the ID the user is created from and its type, derived from the principal kind
+// when set and from the legacy account type otherwise.
+func (m mssqlResourceModel) principal() (string, string, bool) {
+	if m.PrincipalKind.IsNull() {
+		accountType, ok := accountTypeMap[m.AccountType.ValueString()]
+		return m.ObjectId.ValueString(), accountType, ok
+	}
+
+	accountType, ok := mssqlPrincipalKindMap[m.PrincipalKind.ValueString()]
+
+	if mssqlPrincipalUsesClientId(m.PrincipalKind.ValueString()) && m.CreationMode.ValueString() == "sid" {
+		return m.ClientId.ValueString(), accountType, ok
+	}
+
+	return m.ObjectId.ValueString(), accountType, ok
+}
+
+// expectedSid returns the GUID the SID of the user will be derived from, or an empty string when this is only
+// known to the server (the name is looked up or an application is created from its object ID).
+func (m mssqlResourceModel) expectedSid() string {
+	switch m.CreationMode.ValueString() {
+	case "sid":
+		principalId, _, _ := m.principal()
+		return principalId
+	case "object_id":
+		if !mssqlPrincipalUsesClientId(m.PrincipalKind.ValueString()) {
+			return m.ObjectId.ValueString()
+		}
+	}
+
+	return ""
+}
+
 func (d *mssqlResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state mssqlResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -130,7 +330,28 @@ func (d *mssqlResource) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// TODO: Could read status from the database and update the state
+	conn := ssoSql.CreateMssqlConnection(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.Account.ValueString(), state.ObjectId.ValueString(), state.AccountType.ValueString(), mssqlRole(state.Role.ValueString()), mssqlCreationModeMap[state.CreationMode.ValueString()])
+	account, found := conn.ReadAccount(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.Flavor = mssqlFlavorOrDefault(state.Flavor)
+	if state.ReplicationTimeout.IsNull() {
+		state.ReplicationTimeout = types.StringValue("5m")
+	}
+
+	// A disabled account is what is left after a destroy with delete_behavior "disable", so it is no longer managed
+	if !account.CanConnect {
+		resp.Diagnostics.AddWarning("Account disabled", fmt.Sprintf("The account %q exists but has no CONNECT permission, it is treated as deleted. Set %q to %q to enable it again.", state.Account.ValueString(), ifExistsProp, "adopt"))
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -142,28 +363,66 @@ func (d *mssqlResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	accountType, accOk := accountTypeMap[plan.AccountType.ValueString()]
-	role, roleOk := mssqlRoleMap[plan.Role.ValueString()]
+	principalId, accountType, accOk := plan.principal()
+	role := mssqlRole(plan.Role.ValueString())
+	creationMode, modeOk := mssqlCreationModeMap[plan.CreationMode.ValueString()]
 
 	if !accOk {
-		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid account type %q", accountType))
+		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid account type %q / principal kind %q", plan.AccountType.ValueString(), plan.PrincipalKind.ValueString()))
 	}
 
-	if !roleOk {
-		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid role %q", role))
+	if !modeOk {
+		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid creation mode %q", plan.CreationMode.ValueString()))
 	}
 
-	if !accOk || !roleOk {
+	if !accOk || !modeOk {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlConnection(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.Account.ValueString(), principalId, accountType, role, creationMode)
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	existing, exists := conn.ReadAccount(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	conn := ssoSql.CreateMssqlConnection(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), plan.Account.ValueString(), plan.ObjectId.ValueString(), accountType, role)
-	conn.CreateAccount(ctx, &resp.Diagnostics)
+	createAccount(ctx, conn, plan.Account.ValueString(), plan.IfExists.ValueString(), exists, func(diags *diag.Diagnostics) {
+		expectedSid := plan.expectedSid()
+
+		if expectedSid == "" {
+			diags.AddWarning("Account not verified", fmt.Sprintf("The SID of the existing account %q cannot be verified with creation mode %q, it is adopted as is.", plan.Account.ValueString(), plan.CreationMode.ValueString()))
+			return
+		}
+
+		if !strings.EqualFold(existing.Sid, expectedSid) {
+			diags.AddError("Account mismatch", fmt.Sprintf("The existing account %q has SID %q but %q was expected, it belongs to a different principal and cannot be adopted.", plan.Account.ValueString(), existing.Sid, expectedSid))
+		}
+	}, &resp.Diagnostics)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	var replicas []string
+	resp.Diagnostics.Append(plan.VerifyReplicas.ElementsAs(ctx, &replicas, false)...)
+	timeout, err := time.ParseDuration(plan.ReplicationTimeout.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid replication timeout %q", plan.ReplicationTimeout.ValueString()))
+	}
+
+	for _, replica := range replicas {
+		if resp.Diagnostics.HasError() {
+			break
+		}
+
+		conn.WaitForReplica(ctx, &resp.Diagnostics, replica, timeout)
+	}
+
+	// The account exists on the primary, so it is stored even when it has not replicated yet
 	id := conn.Id()
 	plan.ID = types.StringValue(id)
 
@@ -171,7 +430,14 @@ func (d *mssqlResource) Create(ctx context.Context, req resource.CreateRequest,
 }
 
 func (d *mssqlResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Noop (any change requires delete and create)
+	// Only attributes which do not touch the database (e.g. if_exists) can change in place
+	var plan mssqlResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 func (d *mssqlResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -181,6 +447,42 @@ func (d *mssqlResource) Delete(ctx context.Context, req resource.DeleteRequest,
 		return
 	}
 
-	conn := ssoSql.CreateMssqlConnection(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), state.Account.ValueString(), state.ObjectId.ValueString(), state.AccountType.ValueString(), state.Role.ValueString())
+	conn := ssoSql.CreateMssqlConnection(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.Account.ValueString(), state.ObjectId.ValueString(), state.AccountType.ValueString(), mssqlRole(state.Role.ValueString()), mssqlCreationModeMap[state.CreationMode.ValueString()])
+	conn.UsePrimary(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	switch state.DeleteBehavior.ValueString() {
+	case "disable":
+		conn.DisableAccount(ctx, &resp.Diagnostics)
+		return
+	case "revoke_roles":
+		conn.RevokeRoles(ctx, &resp.Diagnostics)
+		return
+	}
+
+	if state.OnDestroyOwnership.ValueString() == "reassign" {
+		conn.ReassignOwnership(ctx, &resp.Diagnostics, utils.ValueStringOrDefault(state.ReassignOwnedTo, "dbo"))
+	} else {
+		owned := conn.ListOwnership(ctx, &resp.Diagnostics)
+
+		if len(owned) > 0 {
+			var list strings.Builder
+			for _, o := range owned {
+				list.WriteString(fmt.Sprintf("\n  - %s", o))
+			}
+
+			resp.Diagnostics.AddError(
+				"Account owns securables",
+				fmt.Sprintf("The account %q cannot be dropped because it owns:%s\n\nTransfer the ownership first or set %q to %q.", state.Account.ValueString(), list.String(), onDestroyOwnershipProp, "reassign"),
+			)
+		}
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	conn.DropAccount(ctx, &resp.Diagnostics)
 }
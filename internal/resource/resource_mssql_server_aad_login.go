@@ -0,0 +1,265 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+	"terraform-provider-sqlsso/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &mssqlLoginResource{}
+	_ resource.ResourceWithImportState    = &mssqlLoginResource{}
+	_ resource.ResourceWithValidateConfig = &mssqlLoginResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewMssqlLogin() resource.Resource {
+	return &mssqlLoginResource{}
+}
+
+type mssqlLoginResource struct {
+}
+
+type mssqlLoginResourceModel struct {
+	ID              types.String `tfsdk:"id"`
+	SqlServer       types.String `tfsdk:"sql_server_dns"`
+	Port            types.Int64  `tfsdk:"port"`
+	Flavor          types.String `tfsdk:"flavor"`
+	Login           types.String `tfsdk:"login_name"`
+	ObjectId        types.String `tfsdk:"object_id"`
+	DefaultDatabase types.String `tfsdk:"default_database"`
+	ServerRoles     types.Set    `tfsdk:"server_roles"`
+}
+
+func (d *mssqlLoginResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_mssql_server_aad_login"
+}
+
+// Schema defines the schema for the resource.
+func (d *mssqlLoginResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_mssql_server_aad_login` creates a server level AAD login for an Azure SQL server or Managed Instance and manages its server role membership.\n\nFor this to work terraform should be run for the configured **Active Directory Admin** account. The resource can be imported using the ID `<sql_server_dns>:<port>/<login_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the SQL server to add the login.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(1433),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			flavorProp: mssqlFlavorAttribute("Only a `managed_instance` supports `default_database`."),
+			loginNameProp: schema.StringAttribute{
+				Description: "The name of the login, for users this is the user principal name and for groups and applications the display name.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			objectIdProp: schema.StringAttribute{
+				Description: "Azure AD object ID for the login, only needed when the name is not unique in Azure AD.",
+				Optional:    true,
+				PlanModifiers: []planmodifier.String{
+					// the object ID cannot be read back, so setting it after an import does not recreate the login
+					stringplanmodifier.RequiresReplaceIf(func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+						resp.RequiresReplace = !req.StateValue.IsNull()
+					}, "Changing the object ID of an existing login requires replacement.", "Changing the object ID of an existing login requires replacement."),
+				},
+			},
+			defaultDatabaseProp: schema.StringAttribute{
+				Description: "The default database of the login (defaults to `master`), only supported on a `managed_instance`.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			serverRolesProp: schema.SetAttribute{
+				Description: "Server roles the login should be a member of (e.g. `##MS_ServerStateReader##`, `##MS_DatabaseManager##`). Roles not listed here are removed from the login. When not set, server role membership is left alone.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+		}}
+}
+
+func (d *mssqlLoginResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config mssqlLoginResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Azure SQL Database and Synapse do not support DEFAULT_DATABASE for Azure AD logins
+	flavor := mssqlFlavorOrDefault(config.Flavor)
+	if !config.DefaultDatabase.IsNull() && !flavor.IsUnknown() && mssqlFlavorMap[flavor.ValueString()] != ssoSql.ManagedInstance {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(defaultDatabaseProp),
+			"Unsupported default database",
+			fmt.Sprintf("%q can only be set when %q is %q.", defaultDatabaseProp, flavorProp, "managed_instance"),
+		)
+	}
+}
+
+func (d *mssqlLoginResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state mssqlLoginResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlLogin(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), "master", state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.Login.ValueString(), state.ObjectId.ValueString(), state.DefaultDatabase.ValueString())
+	login, found := conn.ReadLogin(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.DefaultDatabase = types.StringValue(login.DefaultDatabase)
+	state.Flavor = mssqlFlavorOrDefault(state.Flavor)
+
+	// Server roles are only managed when configured, roles granted outside of terraform are left alone otherwise
+	if !state.ServerRoles.IsNull() {
+		serverRoles, diags := types.SetValueFrom(ctx, types.StringType, login.ServerRoles)
+		resp.Diagnostics.Append(diags...)
+		state.ServerRoles = serverRoles
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+func (d *mssqlLoginResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan mssqlLoginResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var serverRoles []string
+	resp.Diagnostics.Append(plan.ServerRoles.ElementsAs(ctx, &serverRoles, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlLogin(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), "master", plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.Login.ValueString(), plan.ObjectId.ValueString(), utils.ValueStringOrDefault(plan.DefaultDatabase, ""))
+	conn.CreateAccount(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.AddServerRoles(ctx, &resp.Diagnostics, serverRoles)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	login, _ := conn.ReadLogin(ctx, &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+	plan.DefaultDatabase = types.StringValue(login.DefaultDatabase)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlLoginResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state mssqlLoginResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plannedRoles, currentRoles []string
+	resp.Diagnostics.Append(plan.ServerRoles.ElementsAs(ctx, &plannedRoles, false)...)
+	resp.Diagnostics.Append(state.ServerRoles.ElementsAs(ctx, &currentRoles, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlLogin(ssoSql.CreateMssqlServer(plan.SqlServer.ValueString(), "master", plan.Port.ValueInt64(), mssqlFlavorMap[plan.Flavor.ValueString()]), plan.Login.ValueString(), plan.ObjectId.ValueString(), plan.DefaultDatabase.ValueString())
+
+	if !plan.DefaultDatabase.IsUnknown() && !plan.DefaultDatabase.Equal(state.DefaultDatabase) {
+		conn.SetDefaultDatabase(ctx, &resp.Diagnostics)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.ServerRoles.IsNull() {
+		conn.DropServerRoles(ctx, &resp.Diagnostics, utils.Difference(currentRoles, plannedRoles))
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		conn.AddServerRoles(ctx, &resp.Diagnostics, utils.Difference(plannedRoles, currentRoles))
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *mssqlLoginResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state mssqlLoginResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreateMssqlLogin(ssoSql.CreateMssqlServer(state.SqlServer.ValueString(), "master", state.Port.ValueInt64(), mssqlFlavorMap[state.Flavor.ValueString()]), state.Login.ValueString(), state.ObjectId.ValueString(), state.DefaultDatabase.ValueString())
+	conn.DropAccount(ctx, &resp.Diagnostics)
+}
+
+func (d *mssqlLoginResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, port, login, err := ssoSql.ParseMssqlLoginId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(loginNameProp), login)...)
+	// An empty set makes Read pick up the server roles of the imported login
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(serverRolesProp), types.SetValueMust(types.StringType, nil))...)
+}
@@ -0,0 +1,51 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourceMsSlqServerAadLogin(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_MSSQL_SERVER_DNS")
+	loginName := os.Getenv("TF_SQLSSO_LOGIN_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_MSSQL_SERVER_DNS must be set to test MS SQL Server AAD Login")
+	}
+	if len(loginName) == 0 {
+		t.Skip("TF_SQLSSO_LOGIN_NAME must be set for acceptance tests")
+	}
+
+	config := fmt.Sprintf(testAccresourceMsSlqServerAadLogin, serverDns, loginName)
+	expectedId := fmt.Sprint(serverDns, ":1433", "/", loginName)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_mssql_server_aad_login.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_mssql_server_aad_login.example", "server_roles.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_mssql_server_aad_login.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+const testAccresourceMsSlqServerAadLogin = `
+resource "sqlsso_mssql_server_aad_login" "example" {
+  sql_server_dns = "%s"
+	login_name = "%s"
+	server_roles = ["##MS_ServerStateReader##"]
+}
+`
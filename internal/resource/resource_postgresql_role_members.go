@@ -0,0 +1,271 @@
+package resource
+
+import (
+	"context"
+	"slices"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
+	"terraform-provider-sqlsso/internal/utils"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &postgreRoleMembersResource{}
+	_ resource.ResourceWithConfigure      = &postgreRoleMembersResource{}
+	_ resource.ResourceWithImportState    = &postgreRoleMembersResource{}
+	_ resource.ResourceWithValidateConfig = &postgreRoleMembersResource{}
+)
+
+// New is a helper function to simplify the provider implementation.
+func NewPostgreRoleMembers() resource.Resource {
+	return &postgreRoleMembersResource{}
+}
+
+type postgreRoleMembersResource struct {
+	providerData ProviderData
+}
+
+type postgreRoleMembersResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	SqlServer     types.String `tfsdk:"sql_server_dns"`
+	Port          types.Int64  `tfsdk:"port"`
+	Maintenance   types.String `tfsdk:"maintenance_database"`
+	UserName      types.String `tfsdk:"user_name"`
+	RoleName      types.String `tfsdk:"role_name"`
+	Members       types.Set    `tfsdk:"members"`
+	IgnoreMembers types.Set    `tfsdk:"ignore_members"`
+}
+
+func (d *postgreRoleMembersResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_postgresql_role_members"
+}
+
+func (d *postgreRoleMembersResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	d.providerData = configureProviderData(req, resp)
+}
+
+// Schema defines the schema for the resource.
+func (d *postgreRoleMembersResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "`sqlsso_postgresql_role_members` authoritatively manages the members of a role of an Azure Postgresql Flexible server: members added outside of terraform are removed. The login terraform connects as, superusers and the server admins (members of `azure_pg_admin`) are never revoked: they are only listed when they are in `members`. Admin rights are managed with `is_admin` of `sqlsso_postgresql_server_aad_account`, so `azure_pg_admin` cannot be used as `role_name`.\n\nThe resource can be imported using the ID `<sql_server_dns>:<port>/<role_name>`.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			sqlServerDnsProp: schema.StringAttribute{
+				Description: "The DNS name of the Postgres server.",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			portProp: schema.Int64Attribute{
+				Description: "Port to connect to the database server.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(5432),
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.RequiresReplace(),
+				},
+			},
+			maintenanceDatabaseProp: schema.StringAttribute{
+				Description: "The database to connect to, overriding `maintenance_database` of the provider. Roles belong to the server, so any database the login can connect to works.",
+				Optional:    true,
+			},
+			userNameProp: schema.StringAttribute{
+				Description: "The name of the account that will log into the database. By default it is taken from the access token: the UPN of a user or the display name of a service principal.",
+				Optional:    true,
+			},
+			roleNameProp: schema.StringAttribute{
+				Description: "The name of the role (e.g. `pg_monitor`).",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			membersProp:       membersAttribute(),
+			ignoreMembersProp: ignoreMembersAttribute(),
+		}}
+}
+
+func (d *postgreRoleMembersResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config postgreRoleMembersResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	validateMembers(ctx, config.Members, config.IgnoreMembers, &resp.Diagnostics)
+
+	if config.RoleName.ValueString() == "azure_pg_admin" {
+		resp.Diagnostics.AddAttributeError(path.Root(roleNameProp), "Unsupported role", "The members of azure_pg_admin are the server admins, use is_admin of sqlsso_postgresql_server_aad_account instead.")
+	}
+}
+
+func (d *postgreRoleMembersResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state postgreRoleMembersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var configured, ignored []string
+	resp.Diagnostics.Append(state.Members.ElementsAs(ctx, &configured, false)...)
+	resp.Diagnostics.Append(state.IgnoreMembers.ElementsAs(ctx, &ignored, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreatePostgreRole(ssoSql.CreatePostgreServer(state.SqlServer.ValueString(), "", state.Port.ValueInt64(), state.UserName.ValueString(), d.providerData.maintenanceDatabase(state.Maintenance)), state.RoleName.ValueString())
+	found := conn.ReadRole(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	members := conn.ListMembers(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Protected members are never revoked, so they are only listed when configured to keep the plan empty
+	var listed []string
+	for _, member := range members {
+		if !member.Protected || slices.Contains(configured, member.Name) {
+			listed = append(listed, member.Name)
+		}
+	}
+
+	memberSet, diags := types.SetValueFrom(ctx, types.StringType, managedMembers(listed, ignored))
+	resp.Diagnostics.Append(diags...)
+	state.Members = memberSet
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// postgreMemberNames returns the names of all members and of those which may be revoked.
+func postgreMemberNames(members []ssoSql.PostgreRoleMember) ([]string, []string) {
+	var names, revocable []string
+
+	for _, member := range members {
+		names = append(names, member.Name)
+		if !member.Protected {
+			revocable = append(revocable, member.Name)
+		}
+	}
+
+	return names, revocable
+}
+
+// reconcile adds the planned members and removes everyone else who is neither ignored nor protected.
+func (m postgreRoleMembersResourceModel) reconcile(ctx context.Context, maintenanceDatabase string, diags *diag.Diagnostics) {
+	var planned, ignored []string
+	diags.Append(m.Members.ElementsAs(ctx, &planned, false)...)
+	diags.Append(m.IgnoreMembers.ElementsAs(ctx, &ignored, false)...)
+	if diags.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreatePostgreRole(ssoSql.CreatePostgreServer(m.SqlServer.ValueString(), "", m.Port.ValueInt64(), m.UserName.ValueString(), maintenanceDatabase), m.RoleName.ValueString())
+	current, revocable := postgreMemberNames(conn.ListMembers(ctx, diags))
+	if diags.HasError() {
+		return
+	}
+
+	conn.DropMembers(ctx, diags, utils.Difference(managedMembers(revocable, ignored), planned))
+
+	if diags.HasError() {
+		return
+	}
+
+	conn.AddMembers(ctx, diags, utils.Difference(planned, current))
+}
+
+func (d *postgreRoleMembersResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan postgreRoleMembersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.reconcile(ctx, d.providerData.maintenanceDatabase(plan.Maintenance), &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreatePostgreRole(ssoSql.CreatePostgreServer(plan.SqlServer.ValueString(), "", plan.Port.ValueInt64(), plan.UserName.ValueString(), d.providerData.maintenanceDatabase(plan.Maintenance)), plan.RoleName.ValueString())
+	id := conn.Id()
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *postgreRoleMembersResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan postgreRoleMembersResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.reconcile(ctx, d.providerData.maintenanceDatabase(plan.Maintenance), &resp.Diagnostics)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (d *postgreRoleMembersResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state postgreRoleMembersResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var members []string
+	resp.Diagnostics.Append(state.Members.ElementsAs(ctx, &members, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only the declared members are removed, the role itself, ignored and protected members stay
+	conn := ssoSql.CreatePostgreRole(ssoSql.CreatePostgreServer(state.SqlServer.ValueString(), "", state.Port.ValueInt64(), state.UserName.ValueString(), d.providerData.maintenanceDatabase(state.Maintenance)), state.RoleName.ValueString())
+	_, revocable := postgreMemberNames(conn.ListMembers(ctx, &resp.Diagnostics))
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn.DropMembers(ctx, &resp.Diagnostics, utils.Intersection(members, revocable))
+}
+
+func (d *postgreRoleMembersResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, port, roleName, err := ssoSql.ParsePostgreRoleId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(roleNameProp), roleName)...)
+}
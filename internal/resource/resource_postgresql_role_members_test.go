@@ -0,0 +1,70 @@
+package resource_test
+
+import (
+	"fmt"
+	"os"
+	"terraform-provider-sqlsso/internal/acctest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccresourcePostgreRoleMembers(t *testing.T) {
+	serverDns := os.Getenv("TF_SQLSSO_POSTGRE_SERVER_DNS")
+	dbName := os.Getenv("TF_SQLSSO_DB_NAME")
+	userName := os.Getenv("TF_SQLSSO_USER_NAME")
+	accountName := os.Getenv("TF_SQLSSO_ACCOUNT_NAME")
+
+	if len(serverDns) == 0 {
+		t.Skip("TF_SQLSSO_POSTGRE_SERVER_DNS must be set to test Postgres Role Members")
+	}
+	if len(dbName) == 0 {
+		t.Skip("TF_SQLSSO_DB_NAME must be set for acceptance tests")
+	}
+	if len(accountName) == 0 {
+		t.Skip("TF_SQLSSO_ACCOUNT_NAME must be set for acceptance tests")
+	}
+	if len(userName) == 0 {
+		t.Skip("TF_SQLSSO_USER_NAME must be set for acceptance tests")
+	}
+
+	config := fmt.Sprintf(testAccresourcePostgreRoleMembers, serverDns, dbName, userName, accountName)
+	expectedId := fmt.Sprint(serverDns, ":5432", "/", "pg_monitor")
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_postgresql_role_members.example", "id", expectedId),
+					resource.TestCheckResourceAttr("sqlsso_postgresql_role_members.example", "members.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_postgresql_role_members.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// an import has no ignored members, so it lists everyone who is not protected
+				ImportStateVerifyIgnore: []string{"user_name", "ignore_members", "members"},
+			},
+		},
+	})
+}
+
+const testAccresourcePostgreRoleMembers = `
+resource "sqlsso_postgresql_server_aad_account" "example" {
+  sql_server_dns = "%[1]s"
+	database = "%[2]s"
+	user_name = "%[3]s"
+	account_name = "%[4]s"
+}
+
+resource "sqlsso_postgresql_role_members" "example" {
+  sql_server_dns = "%[1]s"
+	user_name = "%[3]s"
+	role_name = "pg_monitor"
+	members = [sqlsso_postgresql_server_aad_account.example.account_name]
+	ignore_members = ["azure_pg_admin", "%[3]s"]
+}
+`
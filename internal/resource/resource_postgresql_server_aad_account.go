@@ -3,14 +3,24 @@ package resource
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 
 	ssoSql "terraform-provider-sqlsso/internal/sql"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
@@ -19,10 +29,21 @@ import (
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &postgreResource{}
+	_ resource.Resource                   = &postgreResource{}
+	_ resource.ResourceWithConfigure      = &postgreResource{}
+	_ resource.ResourceWithImportState    = &postgreResource{}
+	_ resource.ResourceWithValidateConfig = &postgreResource{}
+	_ resource.ResourceWithUpgradeState   = &postgreResource{}
 )
 
-var pglRoleMap = map[string]string{"owner": "ALL PRIVILEGES", "reader": "pg_read_all_data", "writer": "pg_write_all_data"}
+// pglDefaultMemberOf keeps the read access accounts got from the former default role.
+var pglDefaultMemberOf = []string{"pg_read_all_data"}
+
+// pglPrincipalTypeMap holds the principal types of pgaadauth, service covers service principals and managed identities.
+var pglPrincipalTypeMap = map[string]string{"user": "user", "group": "group", "service": "service"}
+
+// postgreImportUserNameEnv optionally supplies user_name on import, as the configuration is not available then.
+const postgreImportUserNameEnv = "SQLSSO_POSTGRESQL_USER_NAME"
 
 // New is a helper function to simplify the provider implementation.
 func NewPostgre() resource.Resource {
@@ -30,26 +51,47 @@ func NewPostgre() resource.Resource {
 }
 
 type postgreResource struct {
+	providerData ProviderData
 }
 
 type postgreResourceModel struct {
-	ID        types.String `tfsdk:"id"`
-	SqlServer types.String `tfsdk:"sql_server_dns"`
-	Database  types.String `tfsdk:"database"`
-	UserName  types.String `tfsdk:"user_name"`
-	Account   types.String `tfsdk:"account_name"`
-	Port      types.Int64  `tfsdk:"port"`
-	Role      types.String `tfsdk:"role"`
+	ID                 types.String `tfsdk:"id"`
+	SqlServer          types.String `tfsdk:"sql_server_dns"`
+	Database           types.String `tfsdk:"database"`
+	UserName           types.String `tfsdk:"user_name"`
+	Account            types.String `tfsdk:"account_name"`
+	Port               types.Int64  `tfsdk:"port"`
+	Maintenance        types.String `tfsdk:"maintenance_database"`
+	ObjectId           types.String `tfsdk:"object_id"`
+	PrincipalType      types.String `tfsdk:"principal_type"`
+	IsAdmin            types.Bool   `tfsdk:"is_admin"`
+	IsMfa              types.Bool   `tfsdk:"is_mfa"`
+	ConnectionLimit    types.Int64  `tfsdk:"connection_limit"`
+	CreateDatabase     types.Bool   `tfsdk:"create_database"`
+	CreateRole         types.Bool   `tfsdk:"create_role"`
+	Inherit            types.Bool   `tfsdk:"inherit"`
+	Settings           types.Map    `tfsdk:"settings"`
+	Privileges         types.Set    `tfsdk:"database_privileges"`
+	MemberOf           types.Set    `tfsdk:"member_of"`
+	IfExists           types.String `tfsdk:"if_exists"`
+	OnDestroyOwnership types.String `tfsdk:"on_destroy_ownership"`
+	ReassignOwnedTo    types.String `tfsdk:"reassign_owned_to"`
+	DeleteBehavior     types.String `tfsdk:"delete_behavior"`
 }
 
 func (d *postgreResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_postgresql_server_aad_account"
 }
 
+func (d *postgreResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	d.providerData = configureProviderData(req, resp)
+}
+
 // Schema defines the schema for the resource.
 func (d *postgreResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		Description: "`sqlsso_postgresql_server_aad_account` enables AAD authentication for an Azure Postgresql Flexible.",
+		Version:     1,
+		Description: "`sqlsso_postgresql_server_aad_account` enables AAD authentication for an Azure Postgresql Flexible.\n\nThe resource can be imported using the ID `<sql_server_dns>:<database>:<port>/<account_name>`. The account used to log in is taken from the access token, or from the `SQLSSO_POSTGRESQL_USER_NAME` environment variable during import and from `user_name` afterwards.\n\nThe former `role` attribute is replaced by `database_privileges` and `member_of`, state is upgraded without changes when the configuration is replaced accordingly: `reader` (the default) by nothing, `writer` by `member_of = [\"pg_write_all_data\"]` and `owner` by `database_privileges = [\"CONNECT\", \"CREATE\", \"TEMPORARY\"]` with `member_of = []`.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
 				Computed: true,
@@ -72,11 +114,8 @@ func (d *postgreResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 				},
 			},
 			userNameProp: schema.StringAttribute{
-				Description: "The name of the account that will log into the database (not currently infered from connection).",
-				Required:    true,
-				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
-				},
+				Description: "The name of the account that will log into the database. By default it is taken from the access token: the UPN of a user or the display name of a service principal. It is only used to connect, so changing it does not replace the account.",
+				Optional:    true,
 			},
 			accountNameProp: schema.StringAttribute{
 				Description: "The name of the account to add to the database.",
@@ -94,21 +133,212 @@ func (d *postgreResource) Schema(_ context.Context, _ resource.SchemaRequest, re
 					int64planmodifier.RequiresReplace(),
 				},
 			},
-			roleProp: schema.StringAttribute{
-				Description: "The role the account should get (e.g. owner, reader, etc.).",
+			maintenanceDatabaseProp: schema.StringAttribute{
+				Description: "The database the Azure AD principal is created and dropped on, overriding `maintenance_database` of the provider. It must have the `pgaadauth` functions, as `postgres` has.",
+				Optional:    true,
+			},
+			objectIdProp: schema.StringAttribute{
+				Description: "The object ID of the Azure AD principal. When set the principal is created from its object ID and `account_name` can be any name, otherwise `account_name` is looked up in Azure AD.",
 				Optional:    true,
 				Computed:    true,
-				Default:     stringdefault.StaticString("reader"),
 				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
 					stringplanmodifier.RequiresReplace(),
 				},
+			},
+			principalTypeProp: schema.StringAttribute{
+				Description: "The type of the Azure AD principal: `user`, `group` or `service` (service principals and managed identities). Required with `object_id`, otherwise the type is resolved by the server.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringInMap(pglPrincipalTypeMap),
+				},
+			},
+			isAdminProp: schema.BoolAttribute{
+				Description: "Makes the account a member of `azure_pg_admin`, the administrators of the server. It is set when the principal is created, so changing it replaces the account.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			isMfaProp: schema.BoolAttribute{
+				Description: "Requires the account to log in with a token obtained through multi-factor authentication. It is set when the principal is created, so changing it replaces the account.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			connectionLimitProp: schema.Int64Attribute{
+				Description: "How many connections the account can have at the same time, `-1` for no limit.",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(-1),
+			},
+			createDatabaseProp: schema.BoolAttribute{
+				Description: "Allows the account to create databases (`CREATEDB`).",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			createRoleProp: schema.BoolAttribute{
+				Description: "Allows the account to create, alter and drop roles (`CREATEROLE`).",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+			inheritProp: schema.BoolAttribute{
+				Description: "Whether the account has the privileges of the roles it is a member of. With `false` (`NOINHERIT`) it has to `SET ROLE` to use them.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+			settingsProp: schema.MapAttribute{
+				Description: "Defaults of settings for the sessions of the account, e.g. `search_path` or `statement_timeout`. Values are read back as Postgres reports them, so use that form (e.g. `30s` rather than `30000`).",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+			},
+			databasePrivilegesProp: schema.SetAttribute{
+				Description: "Privileges the account gets on the database: `CONNECT`, `CREATE` and `TEMPORARY`. Granting them does not make the account the owner of the database.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				Validators: []validator.Set{
+					databasePrivilegesValidator{},
+				},
+			},
+			memberOfProp: schema.SetAttribute{
+				Description: "Roles the account is a member of: predefined roles such as `pg_read_all_data` and `pg_write_all_data` or custom roles (e.g. managed by `sqlsso_postgresql_role_members`). Defaults to `pg_read_all_data`, use `is_admin` for `azure_pg_admin`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{types.StringValue(pglDefaultMemberOf[0])})),
+			},
+			ifExistsProp: ifExistsAttribute(),
+			onDestroyOwnershipProp: schema.StringAttribute{
+				Description: "What to do on destroy when the account owns objects or has privileges in any database of the server: `fail` returns an error listing them and `reassign` runs `REASSIGN OWNED` to `reassign_owned_to` and `DROP OWNED` in every database before dropping the account.",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("fail"),
 				Validators: []validator.String{
-					stringInMap(pglRoleMap),
+					stringInMap(destroyOwnershipMap),
 				},
 			},
+			reassignOwnedToProp: schema.StringAttribute{
+				Description: "The role which takes over ownership when `on_destroy_ownership` is `reassign`, the account terraform logs in with by default.",
+				Optional:    true,
+			},
+			deleteBehaviorProp: deleteBehaviorAttribute("sets `NOLOGIN`"),
 		}}
 }
 
+func (d *postgreResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config postgreResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.ObjectId.IsUnknown() || config.PrincipalType.IsUnknown() {
+		return
+	}
+
+	if !config.ObjectId.IsNull() && config.PrincipalType.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(principalTypeProp),
+			"Missing principal type",
+			fmt.Sprintf("%q is required when %q is set.", principalTypeProp, objectIdProp),
+		)
+	}
+
+	if config.ObjectId.IsNull() && !config.PrincipalType.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root(principalTypeProp),
+			"Unused principal type",
+			fmt.Sprintf("%q is only used with %q, otherwise the type is resolved by the server.", principalTypeProp, objectIdProp),
+		)
+	}
+
+	if !config.MemberOf.IsUnknown() {
+		var memberOf []types.String
+		resp.Diagnostics.Append(config.MemberOf.ElementsAs(ctx, &memberOf, true)...)
+
+		if slices.Contains(memberOf, types.StringValue("azure_pg_admin")) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root(memberOfProp),
+				"Unsupported role",
+				fmt.Sprintf("Use %q to make the account a member of %q.", isAdminProp, "azure_pg_admin"),
+			)
+		}
+	}
+}
+
+// roles returns the configured database privileges and role memberships.
+func (m postgreResourceModel) roles(ctx context.Context, diags *diag.Diagnostics) ([]string, []string) {
+	var privileges, memberOf []string
+	diags.Append(m.Privileges.ElementsAs(ctx, &privileges, false)...)
+	diags.Append(m.MemberOf.ElementsAs(ctx, &memberOf, false)...)
+
+	return privileges, memberOf
+}
+
+// principal returns the configured principal, the object ID and type are empty when they are resolved by the server.
+func (m postgreResourceModel) principal() ssoSql.PostgrePrincipal {
+	return ssoSql.PostgrePrincipal{
+		ObjectId:      m.ObjectId.ValueString(),
+		PrincipalType: m.PrincipalType.ValueString(),
+		IsAdmin:       m.IsAdmin.ValueBool(),
+		IsMfa:         m.IsMfa.ValueBool(),
+	}
+}
+
+// roleOptions returns the configured attributes and settings of the role.
+func (m postgreResourceModel) roleOptions(ctx context.Context, diags *diag.Diagnostics) ssoSql.PostgreRoleOptions {
+	options := ssoSql.PostgreRoleOptions{
+		ConnectionLimit: m.ConnectionLimit.ValueInt64(),
+		CreateDatabase:  m.CreateDatabase.ValueBool(),
+		CreateRole:      m.CreateRole.ValueBool(),
+		Inherit:         m.Inherit.ValueBool(),
+		Settings:        map[string]string{},
+	}
+	diags.Append(m.Settings.ElementsAs(ctx, &options.Settings, false)...)
+
+	return options
+}
+
+// setRoleOptions copies the attributes and settings of the role as found on the server into the model.
+func (m *postgreResourceModel) setRoleOptions(ctx context.Context, options ssoSql.PostgreRoleOptions, diags *diag.Diagnostics) {
+	m.ConnectionLimit = types.Int64Value(options.ConnectionLimit)
+	m.CreateDatabase = types.BoolValue(options.CreateDatabase)
+	m.CreateRole = types.BoolValue(options.CreateRole)
+	m.Inherit = types.BoolValue(options.Inherit)
+
+	settings, d := types.MapValueFrom(ctx, types.StringType, options.Settings)
+	diags.Append(d...)
+	m.Settings = settings
+}
+
+// setPrincipal copies the principal as found on the server into the model. A known
+// object ID is kept as written when it only differs from the server in case.
+func (m *postgreResourceModel) setPrincipal(principal ssoSql.PostgrePrincipal) {
+	if m.ObjectId.IsNull() || m.ObjectId.IsUnknown() || !strings.EqualFold(m.ObjectId.ValueString(), principal.ObjectId) {
+		m.ObjectId = types.StringValue(principal.ObjectId)
+	}
+	m.PrincipalType = types.StringValue(principal.PrincipalType)
+	m.IsAdmin = types.BoolValue(principal.IsAdmin)
+	m.IsMfa = types.BoolValue(principal.IsMfa)
+}
+
 func (d *postgreResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	var state postgreResourceModel
 	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
@@ -116,7 +346,51 @@ func (d *postgreResource) Read(ctx context.Context, req resource.ReadRequest, re
 		return
 	}
 
-	// TODO: Could read status from the database and update the state
+	conn := ssoSql.CreatePostgreConnection(ssoSql.CreatePostgreServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), state.UserName.ValueString(), d.providerData.maintenanceDatabase(state.Maintenance)), state.Account.ValueString(), state.principal(), ssoSql.PostgreRoleOptions{}, nil, nil)
+	account, found := conn.ReadAccount(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !found {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// A role left behind by dropping the Azure AD principal (or recreated by hand) is not the managed account
+	if account.PrincipalType == "" {
+		resp.Diagnostics.AddWarning("Account is not an Azure AD principal", fmt.Sprintf("The role %q exists but is not an Azure AD principal, it is treated as deleted.", state.Account.ValueString()))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	// A disabled account is what is left after a destroy with delete_behavior "disable", so it is no longer managed
+	if !account.CanLogin {
+		resp.Diagnostics.AddWarning("Account disabled", fmt.Sprintf("The account %q exists but cannot log in, it is treated as deleted. Set %q to %q to enable it again.", state.Account.ValueString(), ifExistsProp, "adopt"))
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	state.setPrincipal(account.PostgrePrincipal)
+	state.setRoleOptions(ctx, account.PostgreRoleOptions, &resp.Diagnostics)
+
+	privileges, diags := types.SetValueFrom(ctx, types.StringType, account.DatabasePrivileges)
+	resp.Diagnostics.Append(diags...)
+	state.Privileges = privileges
+
+	memberOf, diags := types.SetValueFrom(ctx, types.StringType, account.MemberOf)
+	resp.Diagnostics.Append(diags...)
+	state.MemberOf = memberOf
+
+	if state.IfExists.IsNull() {
+		state.IfExists = types.StringValue("fail")
+	}
+	if state.DeleteBehavior.IsNull() {
+		state.DeleteBehavior = types.StringValue("drop")
+	}
+	if state.OnDestroyOwnership.IsNull() {
+		state.OnDestroyOwnership = types.StringValue("fail")
+	}
 
 	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -128,21 +402,38 @@ func (d *postgreResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	role, roleOk := pglRoleMap[plan.Role.ValueString()]
+	privileges, memberOf := plan.roles(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreatePostgreConnection(ssoSql.CreatePostgreServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), plan.UserName.ValueString(), d.providerData.maintenanceDatabase(plan.Maintenance)), plan.Account.ValueString(), plan.principal(), plan.roleOptions(ctx, &resp.Diagnostics), privileges, memberOf)
 
-	if !roleOk {
-		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid role %q", role))
+	existing, exists := conn.ReadAccount(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	conn := ssoSql.CreatePostgreConnection(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), plan.UserName.ValueString(), plan.Account.ValueString(), role)
+	createAccount(ctx, conn, plan.Account.ValueString(), plan.IfExists.ValueString(), exists, func(diags *diag.Diagnostics) {
+		verifyPostgrePrincipal(plan, existing, diags)
+	}, &resp.Diagnostics)
 
-	conn.CreateAccount(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The object ID and type are only known once the server resolved the principal
+	created, found := conn.ReadAccount(ctx, &resp.Diagnostics)
+	if !found && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.AddError("Account not found", fmt.Sprintf("The account %q could not be read after it was created.", plan.Account.ValueString()))
+	}
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
+	plan.setPrincipal(created.PostgrePrincipal)
+
 	id := conn.Id()
 	plan.ID = types.StringValue(id)
 
@@ -150,7 +441,30 @@ func (d *postgreResource) Create(ctx context.Context, req resource.CreateRequest
 }
 
 func (d *postgreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Noop (any change requires delete and create)
+	var plan, state postgreResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Changes of the server, database or principal replace the account, everything else is changed in place
+	previous := ssoSql.PostgreAccount{PostgreRoleOptions: state.roleOptions(ctx, &resp.Diagnostics)}
+	previous.DatabasePrivileges, previous.MemberOf = state.roles(ctx, &resp.Diagnostics)
+	privileges, memberOf := plan.roles(ctx, &resp.Diagnostics)
+	options := plan.roleOptions(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreatePostgreConnection(ssoSql.CreatePostgreServer(plan.SqlServer.ValueString(), plan.Database.ValueString(), plan.Port.ValueInt64(), plan.UserName.ValueString(), d.providerData.maintenanceDatabase(plan.Maintenance)), plan.Account.ValueString(), plan.principal(), options, privileges, memberOf)
+	conn.UpdateAccount(ctx, &resp.Diagnostics, previous)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
 }
 
 func (d *postgreResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -160,13 +474,196 @@ func (d *postgreResource) Delete(ctx context.Context, req resource.DeleteRequest
 		return
 	}
 
-	role, roleOk := pglRoleMap[state.Role.ValueString()]
+	privileges, memberOf := state.roles(ctx, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	conn := ssoSql.CreatePostgreConnection(ssoSql.CreatePostgreServer(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), state.UserName.ValueString(), d.providerData.maintenanceDatabase(state.Maintenance)), state.Account.ValueString(), state.principal(), state.roleOptions(ctx, &resp.Diagnostics), privileges, memberOf)
+
+	switch state.DeleteBehavior.ValueString() {
+	case "disable":
+		conn.DisableAccount(ctx, &resp.Diagnostics)
+		return
+	case "revoke_roles":
+		conn.RevokeRoles(ctx, &resp.Diagnostics)
+		return
+	}
+
+	if state.OnDestroyOwnership.ValueString() == "reassign" {
+		conn.ReassignOwned(ctx, &resp.Diagnostics, state.ReassignOwnedTo.ValueString())
+	} else {
+		dependencies := conn.ListDependencies(ctx, &resp.Diagnostics)
+
+		if len(dependencies) > 0 {
+			var list strings.Builder
+			for _, d := range dependencies {
+				list.WriteString(fmt.Sprintf("\n  - %s", d))
+			}
+
+			resp.Diagnostics.AddError(
+				"Account has dependencies",
+				fmt.Sprintf("The account %q cannot be dropped because of:%s\n\nTransfer the ownership and revoke the privileges first or set %q to %q.", state.Account.ValueString(), list.String(), onDestroyOwnershipProp, "reassign"),
+			)
+		}
+	}
 
-	if !roleOk {
-		resp.Diagnostics.AddError("internal error", fmt.Sprintf("Invalid role %q", role))
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	conn := ssoSql.CreatePostgreConnection(state.SqlServer.ValueString(), state.Database.ValueString(), state.Port.ValueInt64(), state.UserName.ValueString(), state.Account.ValueString(), role)
 	conn.DropAccount(ctx, &resp.Diagnostics)
 }
+
+// verifyPostgrePrincipal checks that an existing principal is the one configured before it is adopted.
+func verifyPostgrePrincipal(plan postgreResourceModel, existing ssoSql.PostgreAccount, diags *diag.Diagnostics) {
+	account := plan.Account.ValueString()
+
+	if existing.PrincipalType == "" {
+		diags.AddError("Account mismatch", fmt.Sprintf("The existing role %q is not an Azure AD principal and cannot be adopted.", account))
+		return
+	}
+
+	if !plan.ObjectId.IsUnknown() && !strings.EqualFold(plan.ObjectId.ValueString(), existing.ObjectId) {
+		diags.AddError("Account mismatch", fmt.Sprintf("The existing principal %q has object ID %q, not %q.", account, existing.ObjectId, plan.ObjectId.ValueString()))
+	}
+
+	if !plan.PrincipalType.IsUnknown() && plan.PrincipalType.ValueString() != existing.PrincipalType {
+		diags.AddError("Account mismatch", fmt.Sprintf("The existing principal %q is a %s, not a %s.", account, existing.PrincipalType, plan.PrincipalType.ValueString()))
+	}
+
+	if plan.IsAdmin.ValueBool() != existing.IsAdmin || plan.IsMfa.ValueBool() != existing.IsMfa {
+		diags.AddError("Account mismatch", fmt.Sprintf("The existing principal %q has %q %t and %q %t, which cannot be changed when it is adopted.", account, isAdminProp, existing.IsAdmin, isMfaProp, existing.IsMfa))
+	}
+}
+
+func (d *postgreResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	sqlServer, database, port, account, err := ssoSql.ParsePostgreId(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(sqlServerDnsProp), sqlServer)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(databaseProp), database)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(portProp), port)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(accountNameProp), account)...)
+
+	// Without it the user is taken from the access token, as when user_name is not configured
+	if userName := os.Getenv(postgreImportUserNameEnv); userName != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(userNameProp), userName)...)
+	}
+}
+
+// pglRoleUpgrades maps the former role attribute onto what it granted: database privileges for owner and membership
+// of a predefined role otherwise. It is also what replaces role in the configuration, see the resource description.
+var pglRoleUpgrades = map[string]struct {
+	privileges []string
+	memberOf   []string
+}{
+	"owner":  {privileges: ssoSql.PostgreDatabasePrivileges, memberOf: []string{}},
+	"reader": {privileges: []string{}, memberOf: []string{"pg_read_all_data"}},
+	"writer": {privileges: []string{}, memberOf: []string{"pg_write_all_data"}},
+}
+
+// postgreResourceModelV0 is the state before role was split into database_privileges and member_of.
+type postgreResourceModelV0 struct {
+	ID             types.String `tfsdk:"id"`
+	SqlServer      types.String `tfsdk:"sql_server_dns"`
+	Database       types.String `tfsdk:"database"`
+	UserName       types.String `tfsdk:"user_name"`
+	Account        types.String `tfsdk:"account_name"`
+	Port           types.Int64  `tfsdk:"port"`
+	ObjectId       types.String `tfsdk:"object_id"`
+	PrincipalType  types.String `tfsdk:"principal_type"`
+	IsAdmin        types.Bool   `tfsdk:"is_admin"`
+	IsMfa          types.Bool   `tfsdk:"is_mfa"`
+	Role           types.String `tfsdk:"role"`
+	IfExists       types.String `tfsdk:"if_exists"`
+	DeleteBehavior types.String `tfsdk:"delete_behavior"`
+}
+
+func (d *postgreResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema: &schema.Schema{
+				Attributes: map[string]schema.Attribute{
+					"id":               schema.StringAttribute{Computed: true},
+					sqlServerDnsProp:   schema.StringAttribute{Required: true},
+					databaseProp:       schema.StringAttribute{Required: true},
+					userNameProp:       schema.StringAttribute{Required: true},
+					accountNameProp:    schema.StringAttribute{Required: true},
+					portProp:           schema.Int64Attribute{Optional: true, Computed: true},
+					objectIdProp:       schema.StringAttribute{Optional: true, Computed: true},
+					principalTypeProp:  schema.StringAttribute{Optional: true, Computed: true},
+					isAdminProp:        schema.BoolAttribute{Optional: true, Computed: true},
+					isMfaProp:          schema.BoolAttribute{Optional: true, Computed: true},
+					roleProp:           schema.StringAttribute{Optional: true, Computed: true},
+					ifExistsProp:       schema.StringAttribute{Optional: true, Computed: true},
+					deleteBehaviorProp: schema.StringAttribute{Optional: true, Computed: true},
+				},
+			},
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var prior postgreResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				// reader was the default role
+				role := prior.Role.ValueString()
+				if prior.Role.IsNull() {
+					role = "reader"
+				}
+
+				grants, ok := pglRoleUpgrades[role]
+				if !ok {
+					resp.Diagnostics.AddError("Invalid state", fmt.Sprintf("Unknown role %q in the state of account %q.", role, prior.Account.ValueString()))
+					return
+				}
+
+				privileges, diags := types.SetValueFrom(ctx, types.StringType, grants.privileges)
+				resp.Diagnostics.Append(diags...)
+				memberOf, diags := types.SetValueFrom(ctx, types.StringType, grants.memberOf)
+				resp.Diagnostics.Append(diags...)
+
+				// Attributes added since get their defaults, so the first plan after the upgrade is empty
+				upgraded := postgreResourceModel{
+					ID:                 prior.ID,
+					SqlServer:          prior.SqlServer,
+					Database:           prior.Database,
+					UserName:           prior.UserName,
+					Account:            prior.Account,
+					Port:               prior.Port,
+					ObjectId:           prior.ObjectId,
+					PrincipalType:      prior.PrincipalType,
+					IsAdmin:            prior.IsAdmin,
+					IsMfa:              prior.IsMfa,
+					ConnectionLimit:    types.Int64Value(-1),
+					CreateDatabase:     types.BoolValue(false),
+					CreateRole:         types.BoolValue(false),
+					Inherit:            types.BoolValue(true),
+					Settings:           types.MapValueMust(types.StringType, map[string]attr.Value{}),
+					Privileges:         privileges,
+					MemberOf:           memberOf,
+					IfExists:           prior.IfExists,
+					OnDestroyOwnership: types.StringValue("fail"),
+					DeleteBehavior:     prior.DeleteBehavior,
+				}
+
+				if upgraded.Port.IsNull() {
+					upgraded.Port = types.Int64Value(5432)
+				}
+				if upgraded.IfExists.IsNull() {
+					upgraded.IfExists = types.StringValue("fail")
+				}
+				if upgraded.DeleteBehavior.IsNull() {
+					upgraded.DeleteBehavior = types.StringValue("drop")
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, upgraded)...)
+			},
+		},
+	}
+}
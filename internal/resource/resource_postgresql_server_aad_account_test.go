@@ -1,12 +1,20 @@
 package resource_test
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"terraform-provider-sqlsso/internal/acctest"
+	sqlsso "terraform-provider-sqlsso/internal/resource"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/defaults"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 )
 
 func TestAccresourcePostgreServerAadAccount(t *testing.T) {
@@ -28,9 +36,13 @@ func TestAccresourcePostgreServerAadAccount(t *testing.T) {
 		t.Skip("TF_SQLSSO_USER_NAME must be set for acceptance tests")
 	}
 
-	config := fmt.Sprintf(testAccresourcePostgreServerAadAccount, serverDns, dbName, userName, accountName)
+	config := fmt.Sprintf(testAccresourcePostgreServerAadAccount, serverDns, dbName, userName, accountName, `"CONNECT", "CREATE", "TEMPORARY"`, `"pg_read_all_data"`)
+	updated := fmt.Sprintf(testAccresourcePostgreServerAadAccount, serverDns, dbName, userName, accountName, `"CONNECT"`, `"pg_read_all_data", "pg_write_all_data"`)
 	expectedId := fmt.Sprint(serverDns, ":", dbName, ":5432", "/", accountName)
 
+	// The import reads the account with the same login as the configuration
+	t.Setenv("SQLSSO_POSTGRESQL_USER_NAME", userName)
+
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: acctest.TestAccProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
@@ -38,8 +50,32 @@ func TestAccresourcePostgreServerAadAccount(t *testing.T) {
 				Config: config,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("sqlsso_postgresql_server_aad_account.example", "id", expectedId),
+					resource.TestCheckResourceAttrSet("sqlsso_postgresql_server_aad_account.example", "object_id"),
+					resource.TestCheckResourceAttrSet("sqlsso_postgresql_server_aad_account.example", "principal_type"),
+					resource.TestCheckResourceAttr("sqlsso_postgresql_server_aad_account.example", "is_admin", "false"),
+					resource.TestCheckResourceAttr("sqlsso_postgresql_server_aad_account.example", "database_privileges.#", "3"),
+					resource.TestCheckTypeSetElemAttr("sqlsso_postgresql_server_aad_account.example", "member_of.*", "pg_read_all_data"),
+					resource.TestCheckResourceAttr("sqlsso_postgresql_server_aad_account.example", "connection_limit", "10"),
 				),
 			},
+			{
+				// Privileges and roles change in place
+				Config: updated,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("sqlsso_postgresql_server_aad_account.example", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("sqlsso_postgresql_server_aad_account.example", "database_privileges.#", "1"),
+					resource.TestCheckResourceAttr("sqlsso_postgresql_server_aad_account.example", "member_of.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "sqlsso_postgresql_server_aad_account.example",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -50,6 +86,143 @@ resource "sqlsso_postgresql_server_aad_account" "example" {
 	database = "%s"
 	user_name = "%s"
 	account_name = "%s"
-	role = "owner"
+	database_privileges = [%s]
+	member_of = [%s]
+	connection_limit = 10
 }
 `
+
+// attributeDefault returns the default the schema plans for an attribute which is not configured, nil if it has none.
+func attributeDefault(ctx context.Context, attribute schema.Attribute) (tftypes.Value, bool) {
+	var value interface {
+		ToTerraformValue(context.Context) (tftypes.Value, error)
+	}
+
+	switch a := attribute.(type) {
+	case schema.StringAttribute:
+		if a.Default == nil {
+			return tftypes.Value{}, false
+		}
+		var resp defaults.StringResponse
+		a.Default.DefaultString(ctx, defaults.StringRequest{}, &resp)
+		value = resp.PlanValue
+	case schema.Int64Attribute:
+		if a.Default == nil {
+			return tftypes.Value{}, false
+		}
+		var resp defaults.Int64Response
+		a.Default.DefaultInt64(ctx, defaults.Int64Request{}, &resp)
+		value = resp.PlanValue
+	case schema.BoolAttribute:
+		if a.Default == nil {
+			return tftypes.Value{}, false
+		}
+		var resp defaults.BoolResponse
+		a.Default.DefaultBool(ctx, defaults.BoolRequest{}, &resp)
+		value = resp.PlanValue
+	case schema.MapAttribute:
+		if a.Default == nil {
+			return tftypes.Value{}, false
+		}
+		var resp defaults.MapResponse
+		a.Default.DefaultMap(ctx, defaults.MapRequest{}, &resp)
+		value = resp.PlanValue
+	case schema.SetAttribute:
+		if a.Default == nil {
+			return tftypes.Value{}, false
+		}
+		var resp defaults.SetResponse
+		a.Default.DefaultSet(ctx, defaults.SetRequest{}, &resp)
+		value = resp.PlanValue
+	default:
+		return tftypes.Value{}, false
+	}
+
+	v, err := value.ToTerraformValue(ctx)
+	return v, err == nil
+}
+
+func TestPostgreServerAadAccountUpgradeState(t *testing.T) {
+	ctx := context.Background()
+	r := sqlsso.NewPostgre()
+
+	var schemaResp fwresource.SchemaResponse
+	r.Schema(ctx, fwresource.SchemaRequest{}, &schemaResp)
+	upgrader := r.(fwresource.ResourceWithUpgradeState).UpgradeState(ctx)[0]
+
+	stringSet := func(values ...string) tftypes.Value {
+		elements := []tftypes.Value{}
+		for _, v := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, v))
+		}
+		return tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements)
+	}
+
+	for _, test := range []struct {
+		role   interface{}
+		config map[string]tftypes.Value
+	}{
+		{nil, nil},
+		{"reader", nil},
+		{"writer", map[string]tftypes.Value{"member_of": stringSet("pg_write_all_data")}},
+		{"owner", map[string]tftypes.Value{"database_privileges": stringSet("CONNECT", "CREATE", "TEMPORARY"), "member_of": stringSet()}},
+	} {
+		t.Run(fmt.Sprint(test.role), func(t *testing.T) {
+			prior := map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "example.postgres.database.azure.com:db:5432/jane"),
+				"sql_server_dns":  tftypes.NewValue(tftypes.String, "example.postgres.database.azure.com"),
+				"database":        tftypes.NewValue(tftypes.String, "db"),
+				"user_name":       tftypes.NewValue(tftypes.String, "admin"),
+				"account_name":    tftypes.NewValue(tftypes.String, "jane"),
+				"port":            tftypes.NewValue(tftypes.Number, 5432),
+				"object_id":       tftypes.NewValue(tftypes.String, "00000000-0000-0000-0000-000000000001"),
+				"principal_type":  tftypes.NewValue(tftypes.String, "user"),
+				"is_admin":        tftypes.NewValue(tftypes.Bool, false),
+				"is_mfa":          tftypes.NewValue(tftypes.Bool, false),
+				"role":            tftypes.NewValue(tftypes.String, test.role),
+				"if_exists":       tftypes.NewValue(tftypes.String, "fail"),
+				"delete_behavior": tftypes.NewValue(tftypes.String, "drop"),
+			}
+
+			req := fwresource.UpgradeStateRequest{State: &tfsdk.State{
+				Schema: *upgrader.PriorSchema,
+				Raw:    tftypes.NewValue(upgrader.PriorSchema.Type().TerraformType(ctx), prior),
+			}}
+			resp := fwresource.UpgradeStateResponse{State: tfsdk.State{
+				Schema: schemaResp.Schema,
+				Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+			}}
+			upgrader.StateUpgrader(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("upgrade failed: %v", resp.Diagnostics)
+			}
+
+			var upgraded map[string]tftypes.Value
+			if err := resp.State.Raw.As(&upgraded); err != nil {
+				t.Fatal(err)
+			}
+
+			// The plan takes the configuration, then the default, and keeps the state of computed attributes. The
+			// configuration still holds what the prior schema required.
+			for name, attribute := range schemaResp.Schema.Attributes {
+				configured, ok := test.config[name]
+				if priorAttribute, found := upgrader.PriorSchema.Attributes[name]; !ok && found && priorAttribute.IsRequired() {
+					configured, ok = prior[name]
+				}
+
+				planned := tftypes.NewValue(attribute.GetType().TerraformType(ctx), nil)
+				if ok {
+					planned = configured
+				} else if value, ok := attributeDefault(ctx, attribute); ok {
+					planned = value
+				} else if attribute.IsComputed() {
+					planned = upgraded[name]
+				}
+
+				if !planned.Equal(upgraded[name]) {
+					t.Errorf("%s is upgraded to %v but planned as %v", name, upgraded[name], planned)
+				}
+			}
+		})
+	}
+}
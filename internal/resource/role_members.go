@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func membersAttribute() schema.SetAttribute {
+	return schema.SetAttribute{
+		Description: "All members the role should have. Members not listed here or in `ignore_members` are removed from the role.",
+		ElementType: types.StringType,
+		Required:    true,
+	}
+}
+
+func ignoreMembersAttribute() schema.SetAttribute {
+	return schema.SetAttribute{
+		Description: "Members which are left alone, e.g. break-glass administrators added outside of terraform.",
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+}
+
+// managedMembers leaves out the ignored members. The result is never nil so a role without members reads as an
+// empty set rather than null.
+func managedMembers(members []string, ignored []string) []string {
+	managed := []string{}
+
+	for _, member := range members {
+		if !slices.Contains(ignored, member) {
+			managed = append(managed, member)
+		}
+	}
+
+	return managed
+}
+
+// validateMembers checks that no member is both declared and ignored.
+func validateMembers(ctx context.Context, members types.Set, ignored types.Set, diags *diag.Diagnostics) {
+	if members.IsUnknown() || ignored.IsUnknown() {
+		return
+	}
+
+	var declared, ignoredMembers []types.String
+	diags.Append(members.ElementsAs(ctx, &declared, true)...)
+	diags.Append(ignored.ElementsAs(ctx, &ignoredMembers, true)...)
+
+	for _, member := range declared {
+		if !member.IsUnknown() && slices.Contains(ignoredMembers, member) {
+			diags.AddAttributeError(
+				path.Root(ignoreMembersProp),
+				"Conflicting members",
+				fmt.Sprintf("%q is listed in both %q and %q.", member.ValueString(), membersProp, ignoreMembersProp),
+			)
+		}
+	}
+}
@@ -3,8 +3,12 @@ package resource
 import (
 	"context"
 	"fmt"
+	"slices"
+
+	ssoSql "terraform-provider-sqlsso/internal/sql"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"golang.org/x/exp/maps"
 )
 
@@ -47,3 +51,75 @@ func (v stringInMapValidator) ValidateString(ctx context.Context, req validator.
 		"Unknown value",
 	)
 }
+
+// permissionsValidator checks that every permission in a set is an upper case T-SQL permission name.
+type permissionsValidator struct{}
+
+func (v permissionsValidator) Description(ctx context.Context) string {
+	return "permissions must be upper case T-SQL permission names, e.g. SELECT or VIEW DEFINITION"
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v permissionsValidator) MarkdownDescription(ctx context.Context) string {
+	return "permissions must be upper case T-SQL permission names, e.g. `SELECT` or `VIEW DEFINITION`"
+}
+
+func (v permissionsValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	var permissions []types.String
+	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &permissions, true)...)
+
+	for _, permission := range permissions {
+		if permission.IsUnknown() || permission.IsNull() {
+			continue
+		}
+
+		if !ssoSql.PermissionPattern.MatchString(permission.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Invalid permission",
+				fmt.Sprintf("%q is not a valid permission, use upper case T-SQL permission names such as SELECT or VIEW DEFINITION", permission.ValueString()),
+			)
+		}
+	}
+}
+
+// databasePrivilegesValidator checks that every privilege in a set can be granted on a Postgres database.
+type databasePrivilegesValidator struct{}
+
+func (v databasePrivilegesValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("privileges must be present in: %v", ssoSql.PostgreDatabasePrivileges)
+}
+
+// MarkdownDescription returns a markdown formatted description of the validator's behavior, suitable for a practitioner to understand its impact.
+func (v databasePrivilegesValidator) MarkdownDescription(ctx context.Context) string {
+	return fmt.Sprintf("privileges must be present in: %v", ssoSql.PostgreDatabasePrivileges)
+}
+
+func (v databasePrivilegesValidator) ValidateSet(ctx context.Context, req validator.SetRequest, resp *validator.SetResponse) {
+	// If the value is unknown or null, there is nothing to validate.
+	if req.ConfigValue.IsUnknown() || req.ConfigValue.IsNull() {
+		return
+	}
+
+	var privileges []types.String
+	resp.Diagnostics.Append(req.ConfigValue.ElementsAs(ctx, &privileges, true)...)
+
+	for _, privilege := range privileges {
+		if privilege.IsUnknown() || privilege.IsNull() {
+			continue
+		}
+
+		if !slices.Contains(ssoSql.PostgreDatabasePrivileges, privilege.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				req.Path,
+				"Invalid privilege",
+				fmt.Sprintf("%q is not a database privilege, use one of %v", privilege.ValueString(), ssoSql.PostgreDatabasePrivileges),
+			)
+		}
+	}
+}
@@ -4,40 +4,147 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/microsoft/go-mssqldb/azuread"
 )
 
-type mssqlConnection struct {
-	sqlServer   string
-	database    string
-	port        int64
-	account     string
-	objectId    string
-	accountType string
-	role        string
+// MssqlCreationMode selects the T-SQL used to create the database user.
+type MssqlCreationMode int
+
+const (
+	// CreateWithSid computes the SID from the object ID: CREATE USER ... WITH SID=..., TYPE=...
+	CreateWithSid MssqlCreationMode = iota
+	// CreateFromExternalProvider lets the server resolve the name in Entra: CREATE USER ... FROM EXTERNAL PROVIDER
+	CreateFromExternalProvider
+	// CreateWithObjectId uses the newer syntax: CREATE USER ... FROM EXTERNAL PROVIDER WITH OBJECT_ID = '...'
+	CreateWithObjectId
+)
+
+// MssqlFlavor selects the T-SQL dialect spoken by the server.
+type MssqlFlavor int
+
+const (
+	// AzureSqlDatabase is an Azure SQL database, the default
+	AzureSqlDatabase MssqlFlavor = iota
+	// ManagedInstance is a database of an Azure SQL Managed Instance, which takes the same T-SQL as AzureSqlDatabase
+	ManagedInstance
+	// SynapseDedicated is a dedicated Azure Synapse SQL pool
+	SynapseDedicated
+	// SynapseServerless is a serverless Azure Synapse SQL pool
+	SynapseServerless
+)
+
+// mssqlExec runs the statement built in @sql. QuoteName returns NULL for names longer than 128 characters, which
+// makes the whole statement NULL and EXEC would then silently do nothing.
+const mssqlExec = `IF @sql IS NULL
+				THROW 50000, 'A name is longer than the 128 characters SQL Server allows.', 1;
+			EXEC (@sql)`
+
+// MssqlAccount is a database user as found on the server.
+type MssqlAccount struct {
+	Sid        string
+	Type       string
+	CanConnect bool
 }
 
-func CreateMssqlConnection(sqlServer string, database string, port int64, account string, objectId string, accountType string, role string) mssqlConnection {
-	return mssqlConnection{
-		sqlServer:   sqlServer,
-		database:    database,
-		port:        port,
-		account:     account,
-		objectId:    objectId,
-		accountType: accountType,
-		role:        role,
+// MssqlOwnedSecurable is a schema, object or role owned by a user, which keeps the user from being dropped.
+type MssqlOwnedSecurable struct {
+	Class string
+	Name  string
+}
+
+func (o MssqlOwnedSecurable) String() string {
+	return fmt.Sprint(o.Class, "::", o.Name)
+}
+
+// mssqlServer holds what is needed to connect to a database and is shared by all MS SQL connections.
+type mssqlServer struct {
+	sqlServer string
+	database  string
+	port      int64
+	flavor    MssqlFlavor
+	// primary is where statements are sent when sqlServer holds a read-only geo-secondary, see UsePrimary
+	primary string
+}
+
+func CreateMssqlServer(sqlServer string, database string, port int64, flavor MssqlFlavor) mssqlServer {
+	return mssqlServer{
+		sqlServer: sqlServer,
+		database:  database,
+		port:      port,
+		flavor:    flavor,
 	}
 }
 
-func (c mssqlConnection) getConnectionString() string {
-	return fmt.Sprintf("sqlserver://%s?database=%s&fedauth=ActiveDirectoryDefault", c.sqlServer, c.database)
+func (s mssqlServer) isSynapse() bool {
+	return s.flavor == SynapseDedicated || s.flavor == SynapseServerless
+}
+
+// roleMemberStatement returns a T-SQL expression building the statement which adds (ADD) or removes (DROP) a member
+// of a role, where role and member are T-SQL expressions. Synapse pools do not support ALTER ROLE ... ADD MEMBER.
+func (s mssqlServer) roleMemberStatement(action string, role string, member string) string {
+	if s.isSynapse() {
+		procedure := "sp_addrolemember"
+		if action == "DROP" {
+			procedure = "sp_droprolemember"
+		}
+
+		return `'EXEC ` + procedure + ` ' + QuoteName(` + role + `, '''') + ', ' + QuoteName(` + member + `, '''')`
+	}
+
+	return `'ALTER ROLE ' + QuoteName(` + role + `) + ' ` + action + ` MEMBER ' + QuoteName(` + member + `)`
 }
 
-func (c mssqlConnection) createConnection(ctx context.Context) (*sql.DB, error) {
-	return sql.Open(azuread.DriverName, c.getConnectionString())
+// host returns the server statements are sent to, which is the primary once UsePrimary found one.
+func (s mssqlServer) host() string {
+	if s.primary != "" {
+		return s.primary
+	}
+
+	return s.sqlServer
+}
+
+func (s mssqlServer) getConnectionString() string {
+	return fmt.Sprintf("sqlserver://%s?database=%s&fedauth=ActiveDirectoryDefault", s.host(), s.database)
+}
+
+func (s mssqlServer) createConnection(ctx context.Context) (*sql.DB, error) {
+	return sql.Open(azuread.DriverName, s.getConnectionString())
+}
+
+type mssqlConnection struct {
+	mssqlServer
+	account      string
+	objectId     string
+	accountType  string
+	role         string
+	creationMode MssqlCreationMode
+}
+
+func CreateMssqlConnection(server mssqlServer, account string, objectId string, accountType string, role string, creationMode MssqlCreationMode) mssqlConnection {
+	return mssqlConnection{
+		mssqlServer:  server,
+		account:      account,
+		objectId:     objectId,
+		accountType:  accountType,
+		role:         role,
+		creationMode: creationMode,
+	}
+}
+
+func (c mssqlConnection) createUserStatement() string {
+	switch c.creationMode {
+	case CreateFromExternalProvider:
+		return `SET @sql = 'CREATE USER ' + QuoteName(@account) + ' FROM EXTERNAL PROVIDER'`
+	case CreateWithObjectId:
+		return `SET @sql = 'CREATE USER ' + QuoteName(@account) + ' FROM EXTERNAL PROVIDER WITH OBJECT_ID = ' + QuoteName(@objectId, '''')`
+	default:
+		return `SET @sql = 'CREATE USER ' + QuoteName(@account) + ' WITH SID=' + CONVERT(varchar(64), CAST(CAST(@objectId AS UNIQUEIDENTIFIER) AS VARBINARY(16)), 1) + ', TYPE=' + @accountType`
+	}
 }
 
 func (c mssqlConnection) CreateAccount(ctx context.Context, diags *diag.Diagnostics) {
@@ -46,13 +153,14 @@ func (c mssqlConnection) CreateAccount(ctx context.Context, diags *diag.Diagnost
 	ctx = tflog.SetField(ctx, "objectId", c.objectId)
 	ctx = tflog.SetField(ctx, "accountType", c.accountType)
 	ctx = tflog.SetField(ctx, "role", c.role)
+	ctx = tflog.SetField(ctx, "creationMode", c.creationMode)
 	tflog.Debug(ctx, "Creating account..")
 
 	cmd := `DECLARE @sql nvarchar(max)
-			SET @sql = 'CREATE USER ' + QuoteName(@account) + ' WITH SID=' + CONVERT(varchar(64), CAST(CAST(@objectId AS UNIQUEIDENTIFIER) AS VARBINARY(16)), 1) + ', TYPE=' + @accountType
-			EXEC (@sql)
-			SET @sql = 'ALTER ROLE ' + @role + ' ADD MEMBER ' + QuoteName(@account)
-			EXEC (@sql)`
+			` + c.createUserStatement() + `
+			` + mssqlExec + `
+			SET @sql = ` + c.roleMemberStatement("ADD", "@role", "@account") + `
+			` + mssqlExec
 
 	Execute(ctx, c, diags, cmd,
 		sql.Named("account", c.account),
@@ -62,11 +170,125 @@ func (c mssqlConnection) CreateAccount(ctx context.Context, diags *diag.Diagnost
 	)
 }
 
+// AdoptAccount takes over an existing user by adding it to the configured role. A disabled user is enabled again.
+func (c mssqlConnection) AdoptAccount(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	ctx = tflog.SetField(ctx, "role", c.role)
+	tflog.Debug(ctx, "Adopting account..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'GRANT CONNECT TO ' + QuoteName(@account)
+			` + mssqlExec + `
+			SET @sql = ` + c.roleMemberStatement("ADD", "@role", "@account") + `
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("account", c.account),
+		sql.Named("role", c.role),
+	)
+}
+
+// ReadAccount looks the user up in the database. The SID is returned as a GUID when it was derived from an Azure AD ID.
+func (c mssqlConnection) ReadAccount(ctx context.Context, diags *diag.Diagnostics) (MssqlAccount, bool) {
+	var account MssqlAccount
+
+	cmd := `SELECT CASE WHEN DATALENGTH(p.sid) = 16 THEN CONVERT(varchar(36), CAST(p.sid AS UNIQUEIDENTIFIER)) ELSE '' END, p.type,
+				CASE WHEN EXISTS (
+					SELECT 1 FROM sys.database_permissions dp
+					WHERE dp.grantee_principal_id = p.principal_id AND dp.class = 0 AND dp.permission_name = 'CONNECT' AND dp.state IN ('G', 'W')
+				) THEN CAST(1 AS bit) ELSE CAST(0 AS bit) END
+			FROM sys.database_principals p
+			WHERE p.name = @account`
+
+	found := QueryRow(ctx, c, diags, cmd, []interface{}{sql.Named("account", c.account)}, &account.Sid, &account.Type, &account.CanConnect)
+
+	return account, found
+}
+
+// ListOwnership returns the schemas, objects and roles owned by the user.
+func (c mssqlConnection) ListOwnership(ctx context.Context, diags *diag.Diagnostics) []MssqlOwnedSecurable {
+	var owned []MssqlOwnedSecurable
+
+	cmd := `SELECT 'SCHEMA', name FROM sys.schemas WHERE principal_id = DATABASE_PRINCIPAL_ID(@account)
+			UNION ALL
+			SELECT 'OBJECT', SCHEMA_NAME(schema_id) + '.' + name FROM sys.objects WHERE principal_id = DATABASE_PRINCIPAL_ID(@account)
+			UNION ALL
+			SELECT 'ROLE', name FROM sys.database_principals WHERE type = 'R' AND owning_principal_id = DATABASE_PRINCIPAL_ID(@account)`
+
+	Query(ctx, c, diags, cmd, []interface{}{sql.Named("account", c.account)}, func(rows *sql.Rows) error {
+		var o MssqlOwnedSecurable
+		if err := rows.Scan(&o.Class, &o.Name); err != nil {
+			return err
+		}
+
+		owned = append(owned, o)
+		return nil
+	})
+
+	return owned
+}
+
+// ReassignOwnership transfers everything owned by the user to the given principal.
+func (c mssqlConnection) ReassignOwnership(ctx context.Context, diags *diag.Diagnostics, owner string) {
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	ctx = tflog.SetField(ctx, "owner", owner)
+	tflog.Debug(ctx, "Reassigning ownership..")
+
+	cmd := `DECLARE @sql nvarchar(max) = ''
+			SELECT @sql = @sql + 'ALTER AUTHORIZATION ON SCHEMA::' + QuoteName(name) + ' TO ' + QuoteName(@owner) + ';'
+				FROM sys.schemas WHERE principal_id = DATABASE_PRINCIPAL_ID(@account)
+			SELECT @sql = @sql + 'ALTER AUTHORIZATION ON OBJECT::' + QuoteName(SCHEMA_NAME(schema_id)) + '.' + QuoteName(name) + ' TO ' + QuoteName(@owner) + ';'
+				FROM sys.objects WHERE principal_id = DATABASE_PRINCIPAL_ID(@account)
+			SELECT @sql = @sql + 'ALTER AUTHORIZATION ON ROLE::' + QuoteName(name) + ' TO ' + QuoteName(@owner) + ';'
+				FROM sys.database_principals WHERE type = 'R' AND owning_principal_id = DATABASE_PRINCIPAL_ID(@account)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("account", c.account),
+		sql.Named("owner", owner),
+	)
+}
+
+func (c mssqlConnection) revokeRolesStatement() string {
+	return `SELECT @sql = @sql + ` + c.roleMemberStatement("DROP", "r.name", "@account") + ` + ';'
+			FROM sys.database_role_members m
+			JOIN sys.database_principals r ON r.principal_id = m.role_principal_id
+			WHERE m.member_principal_id = DATABASE_PRINCIPAL_ID(@account)`
+}
+
+// RevokeRoles removes the user from every database role it is a member of.
+func (c mssqlConnection) RevokeRoles(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Revoking roles..")
+
+	cmd := `DECLARE @sql nvarchar(max) = ''
+			` + c.revokeRolesStatement() + `
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd, sql.Named("account", c.account))
+}
+
+// DisableAccount revokes CONNECT and removes the user from its roles. The user and anything it owns are kept.
+func (c mssqlConnection) DisableAccount(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Disabling account..")
+
+	cmd := `DECLARE @sql nvarchar(max) = 'REVOKE CONNECT FROM ' + QuoteName(@account) + ';'
+			` + c.revokeRolesStatement() + `
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd, sql.Named("account", c.account))
+}
+
 func (c mssqlConnection) DropAccount(ctx context.Context, diags *diag.Diagnostics) {
 
 	cmd := `DECLARE @sql nvarchar(max)
 			SET @sql = 'DROP USER ' + QuoteName(@account)
-			EXEC (@sql)`
+			` + mssqlExec
 
 	Execute(ctx, c, diags, cmd, sql.Named("account", c.account))
 }
@@ -74,3 +296,20 @@ func (c mssqlConnection) DropAccount(ctx context.Context, diags *diag.Diagnostic
 func (c mssqlConnection) Id() string {
 	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.account)
 }
+
+// ParseMssqlId splits an ID of the form returned by Id (<sql_server_dns>:<database>:<port>/<name>) into its parts.
+func ParseMssqlId(id string) (string, string, int64, string, error) {
+	parts, name, ok := strings.Cut(id, "/")
+	serverParts := strings.Split(parts, ":")
+
+	if !ok || name == "" || len(serverParts) != 3 || serverParts[0] == "" || serverParts[1] == "" {
+		return "", "", 0, "", fmt.Errorf("expected an ID of the form <sql_server_dns>:<database>:<port>/<name>, got %q", id)
+	}
+
+	port, err := strconv.ParseInt(serverParts[2], 10, 64)
+	if err != nil {
+		return "", "", 0, "", fmt.Errorf("invalid port in ID %q: %s", id, err)
+	}
+
+	return serverParts[0], serverParts[1], port, name, nil
+}
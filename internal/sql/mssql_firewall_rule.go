@@ -0,0 +1,74 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// MssqlFirewallRule is a database level firewall rule as found in sys.database_firewall_rules.
+type MssqlFirewallRule struct {
+	StartIpAddress string
+	EndIpAddress   string
+}
+
+type mssqlFirewallRule struct {
+	mssqlServer
+	rule           string
+	startIpAddress string
+	endIpAddress   string
+}
+
+// CreateMssqlFirewallRule returns a connection for a database level firewall rule. These rules can only be managed
+// through a connection to the database itself.
+func CreateMssqlFirewallRule(server mssqlServer, rule string, startIpAddress string, endIpAddress string) mssqlFirewallRule {
+	return mssqlFirewallRule{
+		mssqlServer:    server,
+		rule:           rule,
+		startIpAddress: startIpAddress,
+		endIpAddress:   endIpAddress,
+	}
+}
+
+// SetRule creates the rule or updates the address range of an existing rule.
+func (c mssqlFirewallRule) SetRule(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "rule", c.rule)
+	ctx = tflog.SetField(ctx, "startIpAddress", c.startIpAddress)
+	ctx = tflog.SetField(ctx, "endIpAddress", c.endIpAddress)
+	tflog.Debug(ctx, "Setting firewall rule..")
+
+	cmd := `EXEC sp_set_database_firewall_rule @name = @rule, @start_ip_address = @startIpAddress, @end_ip_address = @endIpAddress`
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("rule", c.rule),
+		sql.Named("startIpAddress", c.startIpAddress),
+		sql.Named("endIpAddress", c.endIpAddress),
+	)
+}
+
+func (c mssqlFirewallRule) ReadRule(ctx context.Context, diags *diag.Diagnostics) (MssqlFirewallRule, bool) {
+	var rule MssqlFirewallRule
+
+	cmd := `SELECT start_ip_address, end_ip_address
+			FROM sys.database_firewall_rules
+			WHERE name = @rule`
+
+	found := QueryRow(ctx, c, diags, cmd, []interface{}{sql.Named("rule", c.rule)}, &rule.StartIpAddress, &rule.EndIpAddress)
+
+	return rule, found
+}
+
+func (c mssqlFirewallRule) DeleteRule(ctx context.Context, diags *diag.Diagnostics) {
+
+	cmd := `EXEC sp_delete_database_firewall_rule @name = @rule`
+
+	Execute(ctx, c, diags, cmd, sql.Named("rule", c.rule))
+}
+
+func (c mssqlFirewallRule) Id() string {
+	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.rule)
+}
@@ -0,0 +1,166 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// MssqlLogin is a server login as found on the server.
+type MssqlLogin struct {
+	Sid             string
+	DefaultDatabase string
+	ServerRoles     []string
+}
+
+type mssqlLogin struct {
+	mssqlServer
+	login           string
+	objectId        string
+	defaultDatabase string
+}
+
+// CreateMssqlLogin returns a connection for a server login, logins are always managed from the master database. master
+// is not geo-replicated, so logins are written on the configured server and never need UsePrimary.
+func CreateMssqlLogin(server mssqlServer, login string, objectId string, defaultDatabase string) mssqlLogin {
+	server.database = "master"
+
+	return mssqlLogin{
+		mssqlServer:     server,
+		login:           login,
+		objectId:        objectId,
+		defaultDatabase: defaultDatabase,
+	}
+}
+
+func (c mssqlLogin) CreateAccount(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "login", c.login)
+	ctx = tflog.SetField(ctx, "objectId", c.objectId)
+	tflog.Debug(ctx, "Creating login..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'CREATE LOGIN ' + QuoteName(@login) + ' FROM EXTERNAL PROVIDER'
+			IF @objectId <> ''
+				SET @sql = @sql + ' WITH OBJECT_ID = ' + QuoteName(@objectId, '''')
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("login", c.login),
+		sql.Named("objectId", c.objectId),
+	)
+
+	if diags.HasError() || c.defaultDatabase == "" {
+		return
+	}
+
+	c.SetDefaultDatabase(ctx, diags)
+}
+
+// SetDefaultDatabase changes the default database of the login.
+func (c mssqlLogin) SetDefaultDatabase(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "login", c.login)
+	ctx = tflog.SetField(ctx, "defaultDatabase", c.defaultDatabase)
+	tflog.Debug(ctx, "Setting default database..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'ALTER LOGIN ' + QuoteName(@login) + ' WITH DEFAULT_DATABASE = ' + QuoteName(@defaultDatabase)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("login", c.login),
+		sql.Named("defaultDatabase", c.defaultDatabase),
+	)
+}
+
+// AddServerRoles makes the login a member of the given server roles.
+func (c mssqlLogin) AddServerRoles(ctx context.Context, diags *diag.Diagnostics, roles []string) {
+	c.alterServerRoles(ctx, diags, roles, "ADD")
+}
+
+// DropServerRoles removes the login from the given server roles.
+func (c mssqlLogin) DropServerRoles(ctx context.Context, diags *diag.Diagnostics, roles []string) {
+	c.alterServerRoles(ctx, diags, roles, "DROP")
+}
+
+func (c mssqlLogin) alterServerRoles(ctx context.Context, diags *diag.Diagnostics, roles []string, action string) {
+
+	ctx = tflog.SetField(ctx, "login", c.login)
+
+	cmd := mssqlServerRoleStatement(action)
+
+	for _, role := range roles {
+		tflog.Debug(tflog.SetField(ctx, "role", role), fmt.Sprintf("Altering server role (%s member)..", action))
+
+		Execute(ctx, c, diags, cmd,
+			sql.Named("login", c.login),
+			sql.Named("role", role),
+		)
+
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+// mssqlServerRoleStatement returns the T-SQL which adds (ADD) or removes (DROP) the login as member of the role.
+func mssqlServerRoleStatement(action string) string {
+	return `DECLARE @sql nvarchar(max)
+			SET @sql = 'ALTER SERVER ROLE ' + QuoteName(@role) + ' ` + action + ` MEMBER ' + QuoteName(@login)
+			` + mssqlExec
+}
+
+// ReadLogin looks the login up on the server together with the server roles it is a member of.
+func (c mssqlLogin) ReadLogin(ctx context.Context, diags *diag.Diagnostics) (MssqlLogin, bool) {
+	var login MssqlLogin
+
+	cmd := `SELECT CASE WHEN DATALENGTH(sid) = 16 THEN CONVERT(varchar(36), CAST(sid AS UNIQUEIDENTIFIER)) ELSE '' END, ISNULL(default_database_name, '')
+			FROM sys.server_principals
+			WHERE name = @login AND type IN ('E', 'X')`
+
+	found := QueryRow(ctx, c, diags, cmd, []interface{}{sql.Named("login", c.login)}, &login.Sid, &login.DefaultDatabase)
+
+	if !found || diags.HasError() {
+		return login, found
+	}
+
+	cmd = `SELECT r.name
+			FROM sys.server_role_members m
+			JOIN sys.server_principals r ON r.principal_id = m.role_principal_id
+			JOIN sys.server_principals p ON p.principal_id = m.member_principal_id
+			WHERE p.name = @login`
+
+	Query(ctx, c, diags, cmd, []interface{}{sql.Named("login", c.login)}, func(rows *sql.Rows) error {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return err
+		}
+
+		login.ServerRoles = append(login.ServerRoles, role)
+		return nil
+	})
+
+	return login, found
+}
+
+func (c mssqlLogin) DropAccount(ctx context.Context, diags *diag.Diagnostics) {
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'DROP LOGIN ' + QuoteName(@login)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd, sql.Named("login", c.login))
+}
+
+func (c mssqlLogin) Id() string {
+	return fmt.Sprint(c.sqlServer, ":", c.port, "/", c.login)
+}
+
+// ParseMssqlLoginId splits an ID returned by Id of a login into the server, port and login name.
+func ParseMssqlLoginId(id string) (string, int64, string, error) {
+	return parseServerId(id, "login_name")
+}
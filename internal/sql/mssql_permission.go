@@ -0,0 +1,185 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// PermissionPattern matches permission names such as SELECT or VIEW DATABASE STATE. Permissions cannot be quoted
+// in T-SQL, so only names matching it are ever put in a statement.
+var PermissionPattern = regexp.MustCompile(`^[A-Z]+( [A-Z]+)*$`)
+
+// MssqlPermission is a permission as found in sys.database_permissions. State is G (grant), W (grant with grant
+// option) or D (deny), Column is empty unless the permission is on a single column.
+type MssqlPermission struct {
+	Permission string
+	State      string
+	Column     string
+}
+
+type mssqlPermission struct {
+	mssqlServer
+	principal      string
+	securableClass string
+	securable      string
+	columns        []string
+}
+
+// CreateMssqlPermission returns a connection for the permissions of a principal on a securable. The class is
+// DATABASE (the securable is then ignored), SCHEMA or OBJECT, where OBJECT permissions can be limited to columns.
+func CreateMssqlPermission(server mssqlServer, principal string, securableClass string, securable string, columns []string) mssqlPermission {
+	return mssqlPermission{
+		mssqlServer:    server,
+		principal:      principal,
+		securableClass: securableClass,
+		securable:      securable,
+		columns:        columns,
+	}
+}
+
+// Grant grants the permissions, optionally allowing the principal to grant them to others.
+func (c mssqlPermission) Grant(ctx context.Context, diags *diag.Diagnostics, permissions []string, withGrantOption bool) {
+	suffix := ""
+	if withGrantOption {
+		suffix = " WITH GRANT OPTION"
+	}
+
+	c.execute(ctx, diags, "GRANT", permissions, "TO", suffix)
+}
+
+func (c mssqlPermission) Deny(ctx context.Context, diags *diag.Diagnostics, permissions []string) {
+	c.execute(ctx, diags, "DENY", permissions, "TO", "")
+}
+
+// Revoke removes granted or denied permissions. Permissions granted with grant option are revoked with CASCADE,
+// which also revokes what the principal granted to others.
+func (c mssqlPermission) Revoke(ctx context.Context, diags *diag.Diagnostics, permissions []string, cascade bool) {
+	suffix := ""
+	if cascade {
+		suffix = " CASCADE"
+	}
+
+	c.execute(ctx, diags, "REVOKE", permissions, "FROM", suffix)
+}
+
+func (c mssqlPermission) execute(ctx context.Context, diags *diag.Diagnostics, action string, permissions []string, direction string, suffix string) {
+	if len(permissions) == 0 {
+		return
+	}
+
+	for _, permission := range permissions {
+		if !PermissionPattern.MatchString(permission) {
+			diags.AddError("invalid permission", fmt.Sprintf("%q is not a valid permission name", permission))
+			return
+		}
+	}
+
+	columns, err := json.Marshal(c.columns)
+	if err != nil {
+		diags.AddError("error", err.Error())
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "principal", c.principal)
+	ctx = tflog.SetField(ctx, "securableClass", c.securableClass)
+	ctx = tflog.SetField(ctx, "securable", c.securable)
+	ctx = tflog.SetField(ctx, "permissions", permissions)
+	tflog.Debug(ctx, fmt.Sprintf("Executing %s..", action))
+
+	Execute(ctx, c, diags, mssqlPermissionStatement(action, permissions, direction, suffix),
+		sql.Named("class", c.securableClass),
+		sql.Named("securable", c.securable),
+		sql.Named("columns", string(columns)),
+		sql.Named("principal", c.principal),
+	)
+}
+
+// mssqlPermissionStatement returns the T-SQL which grants, denies or revokes the permissions, which must match
+// PermissionPattern as they are written into the statement. The securable, columns and principal are parameters.
+func mssqlPermissionStatement(action string, permissions []string, direction string, suffix string) string {
+	// STRING_AGG skips NULL, a column name which is too long must turn @on into NULL instead of being left out
+	return `DECLARE @on nvarchar(max) = CASE @class
+				WHEN 'SCHEMA' THEN ' ON SCHEMA::' + QuoteName(@securable)
+				WHEN 'OBJECT' THEN ' ON OBJECT::' + CASE WHEN PARSENAME(@securable, 2) IS NULL THEN '' ELSE QuoteName(PARSENAME(@securable, 2)) + '.' END + QuoteName(PARSENAME(@securable, 1))
+				ELSE '' END
+			IF @columns <> '[]' AND @columns <> 'null'
+				SELECT @on = CASE WHEN COUNT(*) = COUNT(QuoteName(value)) THEN @on + ' (' + STRING_AGG(QuoteName(value), ', ') + ')' END FROM OPENJSON(@columns)
+			DECLARE @sql nvarchar(max)
+			SET @sql = '` + action + ` ` + strings.Join(permissions, ", ") + `' + @on + ' ` + direction + ` ' + QuoteName(@principal) + '` + suffix + `'
+			` + mssqlExec
+}
+
+// ReadPermissions returns the permissions the principal has on the securable, for all columns.
+func (c mssqlPermission) ReadPermissions(ctx context.Context, diags *diag.Diagnostics) []MssqlPermission {
+	var permissions []MssqlPermission
+
+	cmd := `SELECT permission_name, state, ISNULL(COL_NAME(major_id, minor_id), '')
+			FROM sys.database_permissions
+			WHERE grantee_principal_id = DATABASE_PRINCIPAL_ID(@principal)
+				AND class = CASE @class WHEN 'OBJECT' THEN 1 WHEN 'SCHEMA' THEN 3 ELSE 0 END
+				AND major_id = CASE @class WHEN 'OBJECT' THEN OBJECT_ID(@securable) WHEN 'SCHEMA' THEN SCHEMA_ID(@securable) ELSE 0 END`
+
+	Query(ctx, c, diags, cmd, []interface{}{sql.Named("principal", c.principal), sql.Named("class", c.securableClass), sql.Named("securable", c.securable)}, func(rows *sql.Rows) error {
+		var p MssqlPermission
+		if err := rows.Scan(&p.Permission, &p.State, &p.Column); err != nil {
+			return err
+		}
+
+		permissions = append(permissions, p)
+		return nil
+	})
+
+	return permissions
+}
+
+// permissionIdEscaper escapes the separator of a permission ID in the principal and securable names, which may
+// contain any character. Names without / or % are written as is.
+var permissionIdEscaper = strings.NewReplacer("%", "%25", "/", "%2F")
+
+func (c mssqlPermission) Id() string {
+	if c.securableClass == "DATABASE" {
+		return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.principal)
+	}
+
+	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", permissionIdEscaper.Replace(c.principal), "/", c.securableClass, "::", permissionIdEscaper.Replace(c.securable))
+}
+
+// ParseMssqlPermissionId splits an ID returned by Id of a schema or object permission into its parts.
+func ParseMssqlPermissionId(id string) (string, string, int64, string, string, string, error) {
+	invalid := fmt.Errorf("expected an ID of the form <sql_server_dns>:<database>:<port>/<principal_name>/<SCHEMA|OBJECT>::<securable_name> with / and %% in names written as %%2F and %%25, got %q", id)
+
+	sqlServer, database, port, name, err := ParseMssqlId(id)
+	if err != nil {
+		return "", "", 0, "", "", "", err
+	}
+
+	principal, securable, ok := strings.Cut(name, "/")
+	if !ok || strings.Contains(securable, "/") {
+		return "", "", 0, "", "", "", invalid
+	}
+
+	securableClass, securable, ok := strings.Cut(securable, "::")
+	if !ok {
+		return "", "", 0, "", "", "", invalid
+	}
+
+	principal, err = url.PathUnescape(principal)
+	if err != nil {
+		return "", "", 0, "", "", "", invalid
+	}
+
+	securable, err = url.PathUnescape(securable)
+	if err != nil || principal == "" || securable == "" {
+		return "", "", 0, "", "", "", invalid
+	}
+
+	return sqlServer, database, port, principal, securableClass, securable, nil
+}
@@ -0,0 +1,48 @@
+package sql
+
+import (
+	"testing"
+)
+
+func TestMssqlPermissionId(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		principal string
+		securable string
+		id        string
+	}{
+		{"plain", "Deploy App", "sales.orders", "server:db:1433/Deploy App/OBJECT::sales.orders"},
+		{"slash", "team/readers", "[a/b].[c/d]", "server:db:1433/team%2Freaders/OBJECT::[a%2Fb].[c%2Fd]"},
+		{"percent", "100%", "50%2F", "server:db:1433/100%25/OBJECT::50%252F"},
+		{"separators", "a::b", "x::y:z", "server:db:1433/a::b/OBJECT::x::y:z"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			id := CreateMssqlPermission(CreateMssqlServer("server", "db", 1433, AzureSqlDatabase), test.principal, "OBJECT", test.securable, nil).Id()
+			if id != test.id {
+				t.Fatalf("got ID %q, expected %q", id, test.id)
+			}
+
+			_, _, _, principal, securableClass, securable, err := ParseMssqlPermissionId(id)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if principal != test.principal || securableClass != "OBJECT" || securable != test.securable {
+				t.Fatalf("got %q, %q, %q back from %q", principal, securableClass, securable, id)
+			}
+		})
+	}
+
+	for _, id := range []string{
+		"server:db:1433/principal",
+		"server:db:1433/principal/sales.orders",
+		"server:db:1433/a/b/SCHEMA::sales",
+		"server:db:1433//SCHEMA::sales",
+		"server:db:1433/principal/SCHEMA::",
+		"server:db:1433/bad%zz/SCHEMA::sales",
+	} {
+		if _, _, _, _, _, _, err := ParseMssqlPermissionId(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
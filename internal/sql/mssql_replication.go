@@ -0,0 +1,114 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// replicaPollInterval is how often a geo-secondary is checked while waiting for replication.
+const replicaPollInterval = 10 * time.Second
+
+// UsePrimary sends all further statements to the primary when the database is a read-only geo-secondary (e.g. after
+// a failover group flipped). Writes call it once before they start, reads stay on the configured server.
+func (s *mssqlServer) UsePrimary(ctx context.Context, diags *diag.Diagnostics) {
+	db, err := s.createConnection(ctx)
+	if err != nil {
+		diags.AddError("error", err.Error())
+		return
+	}
+	defer db.Close()
+
+	primary, err := s.findPrimary(ctx, db)
+	if err != nil {
+		diags.AddError("error", err.Error())
+		return
+	}
+
+	if primary == "" {
+		return
+	}
+
+	tflog.Info(ctx, fmt.Sprintf("Database %s on %s is a read-only geo-secondary, writing to the primary on %s..", s.database, s.host(), primary))
+
+	s.primary = primary
+}
+
+// findPrimary returns the DNS name of the primary server when the database is a read-only geo-secondary and an
+// empty string when it can be written.
+func (s mssqlServer) findPrimary(ctx context.Context, db *sql.DB) (string, error) {
+	var updateability string
+
+	err := db.QueryRowContext(ctx, `SELECT CAST(DATABASEPROPERTYEX(DB_NAME(), 'Updateability') AS varchar(20))`).Scan(&updateability)
+	if err != nil {
+		return "", fmt.Errorf("error checking whether %s on %s can be written: %s", s.database, s.host(), err)
+	}
+
+	if updateability != "READ_ONLY" {
+		return "", nil
+	}
+
+	var partner string
+
+	// role 1 means this database is the secondary of the link, the partner is the primary
+	err = db.QueryRowContext(ctx, `SELECT TOP 1 partner_server FROM sys.dm_geo_replication_link_status WHERE role = 1`).Scan(&partner)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("database %s on %s is read only and not a geo-replication secondary", s.database, s.host())
+	}
+	if err != nil {
+		return "", fmt.Errorf("error looking up the primary of %s on %s: %s", s.database, s.host(), err)
+	}
+
+	// partner_server is the logical server name, the DNS suffix is the one of the configured server
+	_, suffix, ok := strings.Cut(s.host(), ".")
+	if !ok {
+		return partner, nil
+	}
+
+	return partner + "." + suffix, nil
+}
+
+// WaitForReplica waits until the user has been replicated to the same database on a geo-secondary server, or adds
+// an error when it does not show up within timeout.
+func (c mssqlConnection) WaitForReplica(ctx context.Context, diags *diag.Diagnostics, replicaServer string, timeout time.Duration) {
+	replica := c
+	replica.sqlServer = replicaServer
+	replica.primary = ""
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	ctx = tflog.SetField(ctx, "replica", replicaServer)
+	tflog.Debug(ctx, "Waiting for replication..")
+
+	deadline := time.Now().Add(timeout)
+
+	for {
+		var readDiags diag.Diagnostics
+		_, found := replica.ReadAccount(ctx, &readDiags)
+
+		if found && !readDiags.HasError() {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			diags.Append(readDiags...)
+			diags.AddError(
+				"Replication timed out",
+				fmt.Sprintf("The account %q did not show up in %s on the secondary %s within %s.", c.account, c.database, replicaServer, timeout),
+			)
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			diags.AddError("Replication not verified", ctx.Err().Error())
+			return
+		case <-time.After(replicaPollInterval):
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package sql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMssqlPrimaryHost(t *testing.T) {
+	conn := CreateMssqlConnection(CreateMssqlServer("secondary.database.windows.net", "db", 1433, AzureSqlDatabase), "jane", "", "", "", CreateWithSid)
+
+	if !strings.Contains(conn.getConnectionString(), "//secondary.database.windows.net?") {
+		t.Fatalf("expected the configured server before UsePrimary, got %q", conn.getConnectionString())
+	}
+
+	conn.primary = "primary.database.windows.net"
+
+	if !strings.Contains(conn.getConnectionString(), "//primary.database.windows.net?") {
+		t.Fatalf("expected statements and their errors to use the primary, got %q", conn.getConnectionString())
+	}
+
+	if conn.Id() != "secondary.database.windows.net:db:1433/jane" {
+		t.Fatalf("expected the ID to keep the configured server, got %q", conn.Id())
+	}
+}
@@ -0,0 +1,160 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// MssqlRole is a database role as found on the server.
+type MssqlRole struct {
+	Owner    string
+	MemberOf []string
+}
+
+type mssqlRole struct {
+	mssqlServer
+	role  string
+	owner string
+}
+
+func CreateMssqlRole(server mssqlServer, role string, owner string) mssqlRole {
+	return mssqlRole{
+		mssqlServer: server,
+		role:        role,
+		owner:       owner,
+	}
+}
+
+func (c mssqlRole) CreateRole(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "role", c.role)
+	ctx = tflog.SetField(ctx, "owner", c.owner)
+	tflog.Debug(ctx, "Creating role..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'CREATE ROLE ' + QuoteName(@role) + ' AUTHORIZATION ' + QuoteName(@owner)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("role", c.role),
+		sql.Named("owner", c.owner),
+	)
+}
+
+// SetOwner transfers the ownership of the role to the configured owner.
+func (c mssqlRole) SetOwner(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "role", c.role)
+	ctx = tflog.SetField(ctx, "owner", c.owner)
+	tflog.Debug(ctx, "Changing role owner..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'ALTER AUTHORIZATION ON ROLE::' + QuoteName(@role) + ' TO ' + QuoteName(@owner)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("role", c.role),
+		sql.Named("owner", c.owner),
+	)
+}
+
+// AddMemberOf makes the role a member of the given roles.
+func (c mssqlRole) AddMemberOf(ctx context.Context, diags *diag.Diagnostics, roles []string) {
+	for _, memberOf := range roles {
+		CreateMssqlRoleMember(c.mssqlServer, memberOf, c.role).AddMember(ctx, diags)
+
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+// DropMemberOf removes the role from the given roles.
+func (c mssqlRole) DropMemberOf(ctx context.Context, diags *diag.Diagnostics, roles []string) {
+	for _, memberOf := range roles {
+		CreateMssqlRoleMember(c.mssqlServer, memberOf, c.role).DropMember(ctx, diags)
+
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+// ReadRole looks the role up in the database together with the roles it is a member of.
+func (c mssqlRole) ReadRole(ctx context.Context, diags *diag.Diagnostics) (MssqlRole, bool) {
+	var role MssqlRole
+
+	cmd := `SELECT ISNULL(USER_NAME(owning_principal_id), '')
+			FROM sys.database_principals
+			WHERE name = @role AND type = 'R'`
+
+	found := QueryRow(ctx, c, diags, cmd, []interface{}{sql.Named("role", c.role)}, &role.Owner)
+
+	if !found || diags.HasError() {
+		return role, found
+	}
+
+	cmd = `SELECT r.name
+			FROM sys.database_role_members m
+			JOIN sys.database_principals r ON r.principal_id = m.role_principal_id
+			WHERE m.member_principal_id = DATABASE_PRINCIPAL_ID(@role)`
+
+	Query(ctx, c, diags, cmd, []interface{}{sql.Named("role", c.role)}, func(rows *sql.Rows) error {
+		var memberOf string
+		if err := rows.Scan(&memberOf); err != nil {
+			return err
+		}
+
+		role.MemberOf = append(role.MemberOf, memberOf)
+		return nil
+	})
+
+	return role, found
+}
+
+// ListMembers returns the members of the role. dbo is left out as it cannot be removed from db_owner.
+func (c mssqlRole) ListMembers(ctx context.Context, diags *diag.Diagnostics) []string {
+	var members []string
+
+	cmd := `SELECT p.name
+			FROM sys.database_role_members m
+			JOIN sys.database_principals p ON p.principal_id = m.member_principal_id
+			WHERE m.role_principal_id = DATABASE_PRINCIPAL_ID(@role) AND p.name <> 'dbo'`
+
+	Query(ctx, c, diags, cmd, []interface{}{sql.Named("role", c.role)}, func(rows *sql.Rows) error {
+		var member string
+		if err := rows.Scan(&member); err != nil {
+			return err
+		}
+
+		members = append(members, member)
+		return nil
+	})
+
+	return members
+}
+
+// DropRole removes all members from the role before dropping it, as a role with members cannot be dropped.
+func (c mssqlRole) DropRole(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "role", c.role)
+	tflog.Debug(ctx, "Dropping role..")
+
+	cmd := `DECLARE @sql nvarchar(max) = ''
+			SELECT @sql = @sql + ` + c.roleMemberStatement("DROP", "@role", "p.name") + ` + ';'
+			FROM sys.database_role_members m
+			JOIN sys.database_principals p ON p.principal_id = m.member_principal_id
+			WHERE m.role_principal_id = DATABASE_PRINCIPAL_ID(@role)
+			SET @sql = @sql + 'DROP ROLE ' + QuoteName(@role)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd, sql.Named("role", c.role))
+}
+
+func (c mssqlRole) Id() string {
+	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.role)
+}
@@ -0,0 +1,79 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+type mssqlRoleMember struct {
+	mssqlServer
+	role   string
+	member string
+}
+
+func CreateMssqlRoleMember(server mssqlServer, role string, member string) mssqlRoleMember {
+	return mssqlRoleMember{
+		mssqlServer: server,
+		role:        role,
+		member:      member,
+	}
+}
+
+func (c mssqlRoleMember) AddMember(ctx context.Context, diags *diag.Diagnostics) {
+	c.alterRole(ctx, diags, "ADD")
+}
+
+func (c mssqlRoleMember) DropMember(ctx context.Context, diags *diag.Diagnostics) {
+	c.alterRole(ctx, diags, "DROP")
+}
+
+func (c mssqlRoleMember) alterRole(ctx context.Context, diags *diag.Diagnostics, action string) {
+
+	ctx = tflog.SetField(ctx, "role", c.role)
+	ctx = tflog.SetField(ctx, "member", c.member)
+	tflog.Debug(ctx, fmt.Sprintf("Altering role (%s member)..", action))
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = ` + c.roleMemberStatement(action, "@role", "@member") + `
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("role", c.role),
+		sql.Named("member", c.member),
+	)
+}
+
+// ReadMember reports whether the member belongs to the role.
+func (c mssqlRoleMember) ReadMember(ctx context.Context, diags *diag.Diagnostics) bool {
+	var found int
+
+	cmd := `SELECT 1
+			FROM sys.database_role_members
+			WHERE role_principal_id = DATABASE_PRINCIPAL_ID(@role) AND member_principal_id = DATABASE_PRINCIPAL_ID(@member)`
+
+	return QueryRow(ctx, c, diags, cmd, []interface{}{sql.Named("role", c.role), sql.Named("member", c.member)}, &found)
+}
+
+func (c mssqlRoleMember) Id() string {
+	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.role, "/", c.member)
+}
+
+// ParseMssqlRoleMemberId splits an ID returned by Id of a role member into its parts.
+func ParseMssqlRoleMemberId(id string) (string, string, int64, string, string, error) {
+	sqlServer, database, port, name, err := ParseMssqlId(id)
+	if err != nil {
+		return "", "", 0, "", "", err
+	}
+
+	role, member, ok := strings.Cut(name, "/")
+	if !ok || role == "" || member == "" {
+		return "", "", 0, "", "", fmt.Errorf("expected an ID of the form <sql_server_dns>:<database>:<port>/<role_name>/<member_name>, got %q", id)
+	}
+
+	return sqlServer, database, port, role, member, nil
+}
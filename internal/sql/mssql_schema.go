@@ -0,0 +1,167 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// MssqlSchema is a schema as found in the database.
+type MssqlSchema struct {
+	Owner string
+}
+
+// MssqlSchemaObject is an object contained in a schema, which keeps the schema from being dropped.
+type MssqlSchemaObject struct {
+	Type string
+	Name string
+}
+
+func (o MssqlSchemaObject) String() string {
+	return fmt.Sprint(o.Type, " ", o.Name)
+}
+
+type mssqlSchema struct {
+	mssqlServer
+	schema string
+	owner  string
+}
+
+func CreateMssqlSchema(server mssqlServer, schema string, owner string) mssqlSchema {
+	return mssqlSchema{
+		mssqlServer: server,
+		schema:      schema,
+		owner:       owner,
+	}
+}
+
+func (c mssqlSchema) CreateSchema(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "schema", c.schema)
+	ctx = tflog.SetField(ctx, "owner", c.owner)
+	tflog.Debug(ctx, "Creating schema..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'CREATE SCHEMA ' + QuoteName(@schema) + ' AUTHORIZATION ' + QuoteName(@owner)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("schema", c.schema),
+		sql.Named("owner", c.owner),
+	)
+}
+
+// SetOwner transfers the ownership of the schema to the configured owner.
+func (c mssqlSchema) SetOwner(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "schema", c.schema)
+	ctx = tflog.SetField(ctx, "owner", c.owner)
+	tflog.Debug(ctx, "Changing schema owner..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'ALTER AUTHORIZATION ON SCHEMA::' + QuoteName(@schema) + ' TO ' + QuoteName(@owner)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("schema", c.schema),
+		sql.Named("owner", c.owner),
+	)
+}
+
+func (c mssqlSchema) ReadSchema(ctx context.Context, diags *diag.Diagnostics) (MssqlSchema, bool) {
+	var schema MssqlSchema
+
+	cmd := `SELECT ISNULL(USER_NAME(principal_id), '')
+			FROM sys.schemas
+			WHERE name = @schema`
+
+	found := QueryRow(ctx, c, diags, cmd, []interface{}{sql.Named("schema", c.schema)}, &schema.Owner)
+
+	return schema, found
+}
+
+// ListObjects returns the objects and types contained in the schema.
+func (c mssqlSchema) ListObjects(ctx context.Context, diags *diag.Diagnostics) []MssqlSchemaObject {
+	var objects []MssqlSchemaObject
+
+	cmd := `SELECT type_desc, name FROM sys.objects WHERE schema_id = SCHEMA_ID(@schema) AND parent_object_id = 0
+			UNION ALL
+			SELECT 'TYPE', name FROM sys.types WHERE schema_id = SCHEMA_ID(@schema) AND is_user_defined = 1`
+
+	Query(ctx, c, diags, cmd, []interface{}{sql.Named("schema", c.schema)}, func(rows *sql.Rows) error {
+		var o MssqlSchemaObject
+		if err := rows.Scan(&o.Type, &o.Name); err != nil {
+			return err
+		}
+
+		objects = append(objects, o)
+		return nil
+	})
+
+	return objects
+}
+
+// TransferObjects moves the objects and types of the schema to the target schema.
+func (c mssqlSchema) TransferObjects(ctx context.Context, diags *diag.Diagnostics, target string) {
+
+	ctx = tflog.SetField(ctx, "schema", c.schema)
+	ctx = tflog.SetField(ctx, "target", target)
+	tflog.Debug(ctx, "Transferring schema objects..")
+
+	cmd := `DECLARE @sql nvarchar(max) = ''
+			SELECT @sql = @sql + 'ALTER SCHEMA ' + QuoteName(@target) + ' TRANSFER OBJECT::' + QuoteName(@schema) + '.' + QuoteName(name) + ';'
+				FROM sys.objects WHERE schema_id = SCHEMA_ID(@schema) AND parent_object_id = 0
+			SELECT @sql = @sql + 'ALTER SCHEMA ' + QuoteName(@target) + ' TRANSFER TYPE::' + QuoteName(@schema) + '.' + QuoteName(name) + ';'
+				FROM sys.types WHERE schema_id = SCHEMA_ID(@schema) AND is_user_defined = 1
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd,
+		sql.Named("schema", c.schema),
+		sql.Named("target", target),
+	)
+}
+
+// DropObjects drops the objects and types of the schema, starting with the foreign keys referencing its tables so
+// the tables can be dropped in any order.
+func (c mssqlSchema) DropObjects(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "schema", c.schema)
+	tflog.Debug(ctx, "Dropping schema objects..")
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SELECT @sql = ISNULL(STRING_AGG(CAST('ALTER TABLE ' + QuoteName(SCHEMA_NAME(t.schema_id)) + '.' + QuoteName(t.name) + ' DROP CONSTRAINT ' + QuoteName(fk.name) AS nvarchar(max)), ';'), '')
+				FROM sys.foreign_keys fk
+				JOIN sys.tables t ON t.object_id = fk.parent_object_id
+				JOIN sys.tables r ON r.object_id = fk.referenced_object_id
+				WHERE t.schema_id = SCHEMA_ID(@schema) OR r.schema_id = SCHEMA_ID(@schema)
+			` + mssqlExec + `
+
+			SELECT @sql = ISNULL(STRING_AGG(CAST('DROP ' + o.kind + ' ' + QuoteName(@schema) + '.' + QuoteName(o.name) AS nvarchar(max)), ';') WITHIN GROUP (ORDER BY o.priority), '')
+				FROM (
+					SELECT name, CASE type WHEN 'V' THEN 'VIEW' WHEN 'P' THEN 'PROCEDURE' WHEN 'U' THEN 'TABLE' WHEN 'SO' THEN 'SEQUENCE' WHEN 'SN' THEN 'SYNONYM' ELSE 'FUNCTION' END AS kind,
+						CASE type WHEN 'V' THEN 1 WHEN 'P' THEN 2 WHEN 'U' THEN 4 WHEN 'SO' THEN 5 WHEN 'SN' THEN 6 ELSE 3 END AS priority
+					FROM sys.objects
+					WHERE schema_id = SCHEMA_ID(@schema) AND type IN ('V', 'P', 'U', 'SO', 'SN', 'FN', 'IF', 'TF')
+					UNION ALL
+					SELECT name, 'TYPE', 7 FROM sys.types WHERE schema_id = SCHEMA_ID(@schema) AND is_user_defined = 1
+				) o
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd, sql.Named("schema", c.schema))
+}
+
+func (c mssqlSchema) DropSchema(ctx context.Context, diags *diag.Diagnostics) {
+
+	cmd := `DECLARE @sql nvarchar(max)
+			SET @sql = 'DROP SCHEMA ' + QuoteName(@schema)
+			` + mssqlExec
+
+	Execute(ctx, c, diags, cmd, sql.Named("schema", c.schema))
+}
+
+func (c mssqlSchema) Id() string {
+	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.schema)
+}
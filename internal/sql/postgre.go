@@ -4,40 +4,105 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"strconv"
 	"strings"
 
+	"terraform-provider-sqlsso/internal/utils"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-type postgreConnection struct {
-	sqlServer string
-	database  string
-	port      int64
-	user      string
-	account   string
-	role      string
+// PostgrePrincipal is the Azure AD principal behind a role. PrincipalType is user, group or service and is empty
+// when the role is not an Azure AD principal. IsAdmin makes the principal a member of azure_pg_admin.
+type PostgrePrincipal struct {
+	ObjectId      string
+	PrincipalType string
+	IsAdmin       bool
+	IsMfa         bool
 }
 
-func CreatePostgreConnection(sqlServer string, database string, port int64, user string, account string, role string) postgreConnection {
-	return postgreConnection{
-		sqlServer: sqlServer,
-		database:  database,
-		port:      port,
-		user:      user,
-		account:   account,
-		role:      role,
+// PostgreRoleOptions are the attributes of the role and the defaults of settings (e.g. search_path) it gets in
+// every database. A ConnectionLimit of -1 is no limit.
+type PostgreRoleOptions struct {
+	ConnectionLimit int64
+	CreateDatabase  bool
+	CreateRole      bool
+	Inherit         bool
+	Settings        map[string]string
+}
+
+func (o PostgreRoleOptions) equal(other PostgreRoleOptions) bool {
+	return o.ConnectionLimit == other.ConnectionLimit && o.CreateDatabase == other.CreateDatabase && o.CreateRole == other.CreateRole &&
+		o.Inherit == other.Inherit && maps.Equal(o.Settings, other.Settings)
+}
+
+// PostgreAccount is a role as found on the server. DatabasePrivileges are the privileges granted to it on the
+// database of the connection and MemberOf the roles it is a member of, apart from azure_pg_admin.
+type PostgreAccount struct {
+	PostgrePrincipal
+	PostgreRoleOptions
+	CanLogin           bool
+	DatabasePrivileges []string
+	MemberOf           []string
+}
+
+// PostgreDatabasePrivileges are the privileges which can be granted on a database. They are keywords and cannot be
+// quoted, so nothing else is ever written into a GRANT ... ON DATABASE.
+var PostgreDatabasePrivileges = []string{"CONNECT", "CREATE", "TEMPORARY"}
+
+// postgreDefaultMaintenanceDatabase is the maintenance database when none is configured.
+const postgreDefaultMaintenanceDatabase = "postgres"
+
+// postgreServer holds what is needed to connect to a database and is shared by all Postgres connections. An empty
+// user is taken from the access token. Principals are created and dropped on the maintenance database, which has
+// the pgaadauth functions.
+type postgreServer struct {
+	sqlServer           string
+	database            string
+	maintenanceDatabase string
+	port                int64
+	user                string
+}
+
+// CreatePostgreServer returns a server connecting to database, an empty maintenanceDatabase is postgres.
+func CreatePostgreServer(sqlServer string, database string, port int64, user string, maintenanceDatabase string) postgreServer {
+	if maintenanceDatabase == "" {
+		maintenanceDatabase = postgreDefaultMaintenanceDatabase
+	}
+
+	return postgreServer{
+		sqlServer:           sqlServer,
+		database:            database,
+		maintenanceDatabase: maintenanceDatabase,
+		port:                port,
+		user:                user,
 	}
 }
 
-func (c postgreConnection) getConnectionString() string {
-	return fmt.Sprintf("postgres://%v:{password}@%v:%v/%v?sslmode=require", c.user, c.sqlServer, c.port, c.database)
+// getConnectionString returns the connection URL without the password, which is an access token.
+func (s postgreServer) getConnectionString() string {
+	return s.connectionUrl(url.User(s.user)).String()
 }
 
-func (c postgreConnection) createConnection(ctx context.Context) (*sql.DB, error) {
+// connectionUrl escapes the user and database, both can contain spaces (e.g. the display name of an application).
+func (s postgreServer) connectionUrl(user *url.Userinfo) *url.URL {
+	return &url.URL{
+		Scheme:   "postgres",
+		User:     user,
+		Host:     net.JoinHostPort(s.sqlServer, strconv.FormatInt(s.port, 10)),
+		Path:     "/" + s.database,
+		RawQuery: "sslmode=require",
+	}
+}
+
+func (s postgreServer) createConnection(ctx context.Context) (*sql.DB, error) {
 	cred, err := azidentity.NewDefaultAzureCredential(nil)
 	if err != nil {
 		return nil, err
@@ -49,39 +114,259 @@ func (c postgreConnection) createConnection(ctx context.Context) (*sql.DB, error
 		return nil, err
 	}
 
-	connStr := strings.Replace(c.getConnectionString(), "{password}", token.Token, 1)
+	// Without a configured user the name is taken from the token, the two have to match for the login to succeed
+	if s.user == "" {
+		s.user, err = postgreUserFromToken(token.Token)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return sql.Open("postgres", connStr)
+	return sql.Open("postgres", s.connectionUrl(url.UserPassword(s.user, token.Token)).String())
 }
 
-func (c postgreConnection) CreateAccount(ctx context.Context, diags *diag.Diagnostics) {
+type postgreConnection struct {
+	postgreServer
+	account    string
+	principal  PostgrePrincipal
+	options    PostgreRoleOptions
+	privileges []string
+	memberOf   []string
+}
 
-	// Create account has to run on postgres database
-	targetDatabase := c.database
-	c.database = "postgres"
+// CreatePostgreConnection creates a connection for the account. Without an object ID in principal the account name
+// is looked up in Azure AD when the account is created. options are set on the role, privileges are granted on the
+// database of the server and the account is made a member of the memberOf roles.
+func CreatePostgreConnection(server postgreServer, account string, principal PostgrePrincipal, options PostgreRoleOptions, privileges []string, memberOf []string) postgreConnection {
+	return postgreConnection{
+		postgreServer: server,
+		account:       account,
+		principal:     principal,
+		options:       options,
+		privileges:    privileges,
+		memberOf:      memberOf,
+	}
+}
+
+// onMaintenanceDatabase returns the connection to the maintenance database, the statements for the principal itself
+// run there while privileges are granted on the database of the connection.
+func (c postgreConnection) onMaintenanceDatabase() postgreConnection {
+	c.database = c.maintenanceDatabase
+	return c
+}
+
+func (c postgreConnection) CreateAccount(ctx context.Context, diags *diag.Diagnostics) {
 
 	ctx = tflog.SetField(ctx, "account", c.account)
 	tflog.Debug(ctx, "Creating account..")
-	cmd := fmt.Sprintf(`select * from pg_catalog.pgaadauth_create_principal('%s', false, false);`, c.account)
-	Execute(ctx, c, diags, cmd)
+	if c.principal.ObjectId != "" {
+		cmd := `select * from pg_catalog.pgaadauth_create_principal_with_oid($1, $2, $3, $4, $5);`
+		Execute(ctx, c.onMaintenanceDatabase(), diags, cmd, c.account, c.principal.ObjectId, c.principal.PrincipalType, c.principal.IsAdmin, c.principal.IsMfa)
+	} else {
+		cmd := `select * from pg_catalog.pgaadauth_create_principal($1, $2, $3);`
+		Execute(ctx, c.onMaintenanceDatabase(), diags, cmd, c.account, c.principal.IsAdmin, c.principal.IsMfa)
+	}
 
 	if diags.HasError() {
 		return
 	}
 
-	tflog.Debug(ctx, "Account created, creating role..")
-	cmd = fmt.Sprintf(`GRANT %s ON DATABASE %s TO "%s";`, c.role, targetDatabase, c.account)
-	Execute(ctx, c, diags, cmd)
+	tflog.Debug(ctx, "Account created, setting role options..")
+	c.AlterRole(ctx, diags, PostgreRoleOptions{})
+
+	if diags.HasError() {
+		return
+	}
+
+	tflog.Debug(ctx, "Granting privileges..")
+	c.grantRoles(ctx, diags)
 }
 
-func (c postgreConnection) DropAccount(ctx context.Context, diags *diag.Diagnostics) {
+// AdoptAccount takes over an existing principal by granting it the configured privileges and roles. A disabled principal is enabled again.
+func (c postgreConnection) AdoptAccount(ctx context.Context, diags *diag.Diagnostics) {
 
-	targetDatabase := c.database
-	c.database = "postgres"
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Adopting account..")
+	Execute(ctx, c.onMaintenanceDatabase(), diags, postgreAlterRoleStatement(c.account, "LOGIN"))
 
-	tflog.Debug(ctx, "Revoking role..")
-	cmd := fmt.Sprintf(`REVOKE %s ON DATABASE %s FROM "%s";`, c.role, targetDatabase, c.account)
-	Execute(ctx, c, diags, cmd)
+	if diags.HasError() {
+		return
+	}
+
+	// Settings the principal already has are kept, the configured ones are added
+	c.AlterRole(ctx, diags, PostgreRoleOptions{})
+
+	if diags.HasError() {
+		return
+	}
+
+	c.grantRoles(ctx, diags)
+}
+
+// AlterRole sets the configured role options in one transaction. Settings in previous which are no longer
+// configured are removed.
+func (c postgreConnection) AlterRole(ctx context.Context, diags *diag.Diagnostics, previous PostgreRoleOptions) {
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Altering role..")
+	executeInTransaction(ctx, c.onMaintenanceDatabase(), diags, c.roleOptionsStatements(previous))
+}
+
+func (c postgreConnection) roleOptionsStatements(previous PostgreRoleOptions) []statement {
+	statements := []statement{{command: postgreRoleOptionsStatement(c.account, c.options)}}
+
+	for setting, value := range c.options.Settings {
+		statements = append(statements,
+			statement{command: `SELECT pg_catalog.set_config($1, $2, true);`, args: []interface{}{setting, value}},
+			statement{command: postgreSetFromCurrentStatement(c.account, setting)},
+		)
+	}
+
+	for setting := range previous.Settings {
+		if _, ok := c.options.Settings[setting]; !ok {
+			statements = append(statements, statement{command: postgreResetStatement(c.account, setting)})
+		}
+	}
+
+	return statements
+}
+
+// UpdateAccount changes the account from previous to the configured role options, privileges and role memberships.
+// Everything runs in one transaction on the database, so a failing grant leaves the account as it was.
+func (c postgreConnection) UpdateAccount(ctx context.Context, diags *diag.Diagnostics, previous PostgreAccount) {
+	var statements []statement
+
+	if !c.options.equal(previous.PostgreRoleOptions) {
+		statements = append(statements, c.roleOptionsStatements(previous.PostgreRoleOptions)...)
+	}
+
+	if revoked := utils.Difference(previous.DatabasePrivileges, c.privileges); len(revoked) > 0 {
+		statements = append(statements, statement{command: postgreRevokePrivilegesStatement(revoked, c.database, c.account)})
+	}
+	if granted := utils.Difference(c.privileges, previous.DatabasePrivileges); len(granted) > 0 {
+		statements = append(statements, statement{command: postgreGrantPrivilegesStatement(granted, c.database, c.account)})
+	}
+
+	if revoked := utils.Difference(previous.MemberOf, c.memberOf); len(revoked) > 0 {
+		statements = append(statements, statement{command: postgreRevokeRolesStatement(revoked, c.account)})
+	}
+	if granted := utils.Difference(c.memberOf, previous.MemberOf); len(granted) > 0 {
+		statements = append(statements, statement{command: postgreGrantRolesStatement(granted, c.account)})
+	}
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Updating account..")
+	executeInTransaction(ctx, c, diags, statements)
+}
+
+// grantRoles grants the configured privileges on the database and the configured role memberships.
+func (c postgreConnection) grantRoles(ctx context.Context, diags *diag.Diagnostics) {
+	if len(c.privileges) > 0 {
+		Execute(ctx, c, diags, postgreGrantPrivilegesStatement(c.privileges, c.database, c.account))
+	}
+
+	if len(c.memberOf) > 0 && !diags.HasError() {
+		Execute(ctx, c, diags, postgreGrantRolesStatement(c.memberOf, c.account))
+	}
+}
+
+// revokePrivileges revokes every privilege of the account on the database, the configured ones or not.
+func (c postgreConnection) revokePrivileges(ctx context.Context, diags *diag.Diagnostics) {
+	Execute(ctx, c, diags, postgreRevokePrivilegesStatement(PostgreDatabasePrivileges, c.database, c.account))
+}
+
+// ReadAccount looks the role up on the server together with its Azure AD details, its privileges on the database
+// and its role memberships. The Azure AD details come from pgaadauth_list_principals, falling back to the security label
+// pgaadauth puts on the role (aadauth,oid=<object id>,type=<principal type>) and azure_pg_admin membership.
+func (c postgreConnection) ReadAccount(ctx context.Context, diags *diag.Diagnostics) (PostgreAccount, bool) {
+	var account PostgreAccount
+
+	cmd := `SELECT COALESCE(p.objectid::text, substring(l.label from 'oid=([^,]+)'), ''),
+				COALESCE(p.principaltype::text, substring(l.label from 'type=([^,]+)'), ''),
+				COALESCE(p.isadmin::int = 1, EXISTS (SELECT 1 FROM pg_catalog.pg_auth_members m JOIN pg_catalog.pg_roles a ON a.oid = m.roleid WHERE m.member = r.oid AND a.rolname = 'azure_pg_admin')),
+				COALESCE(p.ismfa::int = 1, false),
+				r.rolcanlogin,
+				r.rolconnlimit,
+				r.rolcreatedb,
+				r.rolcreaterole,
+				r.rolinherit,
+				COALESCE((SELECT s.setconfig FROM pg_catalog.pg_db_role_setting s WHERE s.setrole = r.oid AND s.setdatabase = 0), '{}'),
+				ARRAY(SELECT a.privilege_type FROM pg_catalog.pg_database d, aclexplode(d.datacl) a
+					WHERE d.datname = $2 AND a.grantee = r.oid ORDER BY 1),
+				ARRAY(SELECT g.rolname FROM pg_catalog.pg_auth_members m JOIN pg_catalog.pg_roles g ON g.oid = m.roleid
+					WHERE m.member = r.oid AND g.rolname <> 'azure_pg_admin' ORDER BY 1)
+			FROM pg_catalog.pg_roles r
+			LEFT JOIN pg_catalog.pgaadauth_list_principals(false) p ON p.rolname = r.rolname
+			LEFT JOIN pg_catalog.pg_shseclabel l ON l.objoid = r.oid AND l.classoid = 'pg_catalog.pg_authid'::regclass AND l.provider = 'pgaadauth'
+			WHERE r.rolname = $1`
+
+	var settings []string
+	found := QueryRow(ctx, c.onMaintenanceDatabase(), diags, cmd, []interface{}{c.account, c.database}, &account.ObjectId, &account.PrincipalType, &account.IsAdmin, &account.IsMfa, &account.CanLogin,
+		&account.ConnectionLimit, &account.CreateDatabase, &account.CreateRole, &account.Inherit, pq.Array(&settings), pq.Array(&account.DatabasePrivileges), pq.Array(&account.MemberOf))
+
+	// Settings are stored as name=value
+	account.Settings = map[string]string{}
+	for _, setting := range settings {
+		if name, value, ok := strings.Cut(setting, "="); ok {
+			account.Settings[name] = value
+		}
+	}
+
+	return account, found
+}
+
+// RevokeRoles revokes the privileges on the database and every role membership of the principal.
+func (c postgreConnection) RevokeRoles(ctx context.Context, diags *diag.Diagnostics) {
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Revoking roles..")
+	c.revokePrivileges(ctx, diags)
+
+	if diags.HasError() {
+		return
+	}
+
+	var roles []string
+
+	cmd := `SELECT r.rolname
+			FROM pg_catalog.pg_auth_members m
+			JOIN pg_catalog.pg_roles r ON r.oid = m.roleid
+			WHERE m.member = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1)`
+
+	Query(ctx, c, diags, cmd, []interface{}{c.account}, func(rows *sql.Rows) error {
+		var role string
+		if err := rows.Scan(&role); err != nil {
+			return err
+		}
+
+		roles = append(roles, role)
+		return nil
+	})
+
+	if diags.HasError() || len(roles) == 0 {
+		return
+	}
+
+	Execute(ctx, c, diags, postgreRevokeRolesStatement(roles, c.account))
+}
+
+// DisableAccount sets NOLOGIN and revokes the roles of the principal. The principal and anything it owns are kept.
+func (c postgreConnection) DisableAccount(ctx context.Context, diags *diag.Diagnostics) {
+
+	c.RevokeRoles(ctx, diags)
+
+	if diags.HasError() {
+		return
+	}
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	tflog.Debug(ctx, "Disabling account..")
+	Execute(ctx, c.onMaintenanceDatabase(), diags, postgreAlterRoleStatement(c.account, "NOLOGIN"))
+}
+
+func (c postgreConnection) DropAccount(ctx context.Context, diags *diag.Diagnostics) {
+
+	tflog.Debug(ctx, "Revoking privileges..")
+	c.revokePrivileges(ctx, diags)
 
 	if diags.HasError() {
 		return
@@ -89,10 +374,14 @@ func (c postgreConnection) DropAccount(ctx context.Context, diags *diag.Diagnost
 
 	ctx = tflog.SetField(ctx, "account", c.account)
 	tflog.Debug(ctx, "dropping account..")
-	cmd = fmt.Sprintf(`drop user "%s";`, c.account)
-	Execute(ctx, c, diags, cmd)
+	Execute(ctx, c.onMaintenanceDatabase(), diags, postgreDropRoleStatement(c.account))
 }
 
 func (c postgreConnection) Id() string {
 	return fmt.Sprint(c.sqlServer, ":", c.database, ":", c.port, "/", c.account)
 }
+
+// ParsePostgreId splits an ID returned by Id into its parts, it has the same form as the MS SQL IDs.
+func ParsePostgreId(id string) (string, string, int64, string, error) {
+	return ParseMssqlId(id)
+}
@@ -0,0 +1,112 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// PostgreDependency is an object in a database which is owned by a role or on which it has privileges, either keeps
+// the role from being dropped.
+type PostgreDependency struct {
+	Database string
+	Object   string
+	Owned    bool
+}
+
+func (d PostgreDependency) String() string {
+	if d.Owned {
+		return fmt.Sprint(d.Database, ": owns ", d.Object)
+	}
+
+	return fmt.Sprint(d.Database, ": privileges on ", d.Object)
+}
+
+// listDatabases returns the databases of the server the login can connect to.
+func (c postgreConnection) listDatabases(ctx context.Context, diags *diag.Diagnostics) []string {
+	var databases []string
+
+	cmd := `SELECT datname FROM pg_catalog.pg_database
+			WHERE datallowconn AND NOT datistemplate AND has_database_privilege(datname, 'CONNECT')
+			ORDER BY 1`
+
+	Query(ctx, c.onMaintenanceDatabase(), diags, cmd, []interface{}{}, func(rows *sql.Rows) error {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return err
+		}
+
+		databases = append(databases, database)
+		return nil
+	})
+
+	return databases
+}
+
+// ListDependencies returns what keeps the account from being dropped in every database of the server. The
+// privileges on the database of the connection are left out, they are revoked when the account is dropped.
+func (c postgreConnection) ListDependencies(ctx context.Context, diags *diag.Diagnostics) []PostgreDependency {
+	var dependencies []PostgreDependency
+
+	// Shared objects such as databases are only listed once, from the maintenance database
+	cmd := `SELECT current_database(), pg_catalog.pg_describe_object(s.classid, s.objid, s.objsubid), s.deptype = 'o'
+			FROM pg_catalog.pg_shdepend s
+			WHERE s.refclassid = 'pg_catalog.pg_authid'::regclass
+				AND s.refobjid = (SELECT oid FROM pg_catalog.pg_roles WHERE rolname = $1)
+				AND s.deptype IN ('o', 'a')
+				AND (s.dbid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = current_database())
+					OR (s.dbid = 0 AND current_database() = $2))
+				AND NOT (s.classid = 'pg_catalog.pg_database'::regclass AND s.deptype = 'a'
+					AND s.objid = (SELECT oid FROM pg_catalog.pg_database WHERE datname = $3))
+			ORDER BY 2`
+
+	for _, database := range c.listDatabases(ctx, diags) {
+		if diags.HasError() {
+			return nil
+		}
+
+		conn := c
+		conn.database = database
+
+		Query(ctx, conn, diags, cmd, []interface{}{c.account, c.maintenanceDatabase, c.database}, func(rows *sql.Rows) error {
+			var d PostgreDependency
+			if err := rows.Scan(&d.Database, &d.Object, &d.Owned); err != nil {
+				return err
+			}
+
+			dependencies = append(dependencies, d)
+			return nil
+		})
+	}
+
+	return dependencies
+}
+
+// ReassignOwned transfers everything the account owns to owner and drops its remaining privileges, in every
+// database of the server. An empty owner is the login terraform runs as.
+func (c postgreConnection) ReassignOwned(ctx context.Context, diags *diag.Diagnostics, owner string) {
+
+	ctx = tflog.SetField(ctx, "account", c.account)
+	ctx = tflog.SetField(ctx, "owner", owner)
+
+	for _, database := range c.listDatabases(ctx, diags) {
+		if diags.HasError() {
+			return
+		}
+
+		conn := c
+		conn.database = database
+
+		tflog.Debug(tflog.SetField(ctx, "database", database), "Reassigning owned objects..")
+		Execute(ctx, conn, diags, postgreReassignOwnedStatement(c.account, owner))
+
+		if diags.HasError() {
+			return
+		}
+
+		Execute(ctx, conn, diags, postgreDropOwnedStatement(c.account))
+	}
+}
@@ -0,0 +1,101 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// PostgreRoleMember is a direct member of a role. Protected members are the login of the connection, superusers and
+// the server admins (members of azure_pg_admin), they are never revoked so that the provider cannot lock itself out.
+type PostgreRoleMember struct {
+	Name      string
+	Protected bool
+}
+
+type postgreRole struct {
+	postgreServer
+	role string
+}
+
+// CreatePostgreRole returns a connection for the members of a role. Roles belong to the server, so it always
+// connects to the maintenance database.
+func CreatePostgreRole(server postgreServer, role string) postgreRole {
+	server.database = server.maintenanceDatabase
+
+	return postgreRole{
+		postgreServer: server,
+		role:          role,
+	}
+}
+
+// ReadRole reports whether the role exists.
+func (c postgreRole) ReadRole(ctx context.Context, diags *diag.Diagnostics) bool {
+	var found int
+
+	cmd := `SELECT 1 FROM pg_catalog.pg_roles WHERE rolname = $1`
+
+	return QueryRow(ctx, c, diags, cmd, []interface{}{c.role}, &found)
+}
+
+// ListMembers returns the roles which are a direct member of the role.
+func (c postgreRole) ListMembers(ctx context.Context, diags *diag.Diagnostics) []PostgreRoleMember {
+	var members []PostgreRoleMember
+
+	cmd := `SELECT m.rolname, m.rolname = current_user OR m.rolsuper
+				OR EXISTS (SELECT 1 FROM pg_catalog.pg_auth_members a JOIN pg_catalog.pg_roles g ON g.oid = a.roleid WHERE a.member = m.oid AND g.rolname = 'azure_pg_admin')
+			FROM pg_catalog.pg_auth_members am
+			JOIN pg_catalog.pg_roles r ON r.oid = am.roleid
+			JOIN pg_catalog.pg_roles m ON m.oid = am.member
+			WHERE r.rolname = $1`
+
+	Query(ctx, c, diags, cmd, []interface{}{c.role}, func(rows *sql.Rows) error {
+		var member PostgreRoleMember
+		if err := rows.Scan(&member.Name, &member.Protected); err != nil {
+			return err
+		}
+
+		members = append(members, member)
+		return nil
+	})
+
+	return members
+}
+
+// AddMembers grants the role to the given members.
+func (c postgreRole) AddMembers(ctx context.Context, diags *diag.Diagnostics, members []string) {
+	for _, member := range members {
+		tflog.Debug(tflog.SetField(ctx, "member", member), "Granting role..")
+
+		Execute(ctx, c, diags, postgreGrantRolesStatement([]string{c.role}, member))
+
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+// DropMembers revokes the role from the given members.
+func (c postgreRole) DropMembers(ctx context.Context, diags *diag.Diagnostics, members []string) {
+	for _, member := range members {
+		tflog.Debug(tflog.SetField(ctx, "member", member), "Revoking role..")
+
+		Execute(ctx, c, diags, postgreRevokeRolesStatement([]string{c.role}, member))
+
+		if diags.HasError() {
+			return
+		}
+	}
+}
+
+func (c postgreRole) Id() string {
+	return fmt.Sprint(c.sqlServer, ":", c.port, "/", c.role)
+}
+
+// ParsePostgreRoleId splits an ID returned by Id of a role into the server, port and role name.
+func ParsePostgreRoleId(id string) (string, int64, string, error) {
+	return parseServerId(id, "role_name")
+}
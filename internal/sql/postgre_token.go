@@ -0,0 +1,52 @@
+package sql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// postgreUserClaims are the token claims holding the name Postgres knows the principal by, in the order they are
+// tried: the UPN of users and the display name of service principals and managed identities.
+var postgreUserClaims = []string{"upn", "preferred_username", "unique_name", "app_displayname"}
+
+// postgreUserFromToken returns the name to log in with from the claims of the access token. The token is not
+// validated, it was just issued to us and the server checks it anyway.
+func postgreUserFromToken(token string) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", errors.New("the access token is not a JWT")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return "", fmt.Errorf("decoding the access token: %w", err)
+	}
+
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return "", fmt.Errorf("decoding the access token: %w", err)
+	}
+
+	for _, claim := range postgreUserClaims {
+		if user, ok := claims[claim].(string); ok && user != "" {
+			return user, nil
+		}
+	}
+
+	// Without a name the object ID at least tells which principal the token was issued to
+	principal := "terraform runs as"
+	if oid, ok := claims["oid"].(string); ok && oid != "" {
+		principal = fmt.Sprintf("with object ID %s", oid)
+	}
+
+	checked := make([]string, len(postgreUserClaims))
+	for i, claim := range postgreUserClaims {
+		checked[i] = strconv.Quote(claim)
+	}
+
+	return "", fmt.Errorf("the access token has none of the claims %s holding the name to log in with, set user_name explicitly to the Postgres role of the principal %s", strings.Join(checked, ", "), principal)
+}
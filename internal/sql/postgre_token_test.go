@@ -0,0 +1,40 @@
+package sql
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestPostgreUserFromToken(t *testing.T) {
+	token := func(claims string) string {
+		return "eyJhbGciOiJSUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".c2lnbmF0dXJl"
+	}
+
+	for _, test := range []struct {
+		name  string
+		token string
+		user  string
+		err   string
+	}{
+		{"user", token(`{"upn":"jane@contoso.com","preferred_username":"jane.doe@contoso.com"}`), "jane@contoso.com", ""},
+		{"guest", token(`{"preferred_username":"jane@fabrikam.com"}`), "jane@fabrikam.com", ""},
+		{"service principal", token(`{"app_displayname":"Deploy App","oid":"00000000-0000-0000-0000-000000000000"}`), "Deploy App", ""},
+		{"empty claim", token(`{"upn":"","app_displayname":"Deploy App"}`), "Deploy App", ""},
+		{"no claim", token(`{"oid":"00000000-0000-0000-0000-000000000001"}`), "", `none of the claims "upn", "preferred_username", "unique_name", "app_displayname" holding the name to log in with, set user_name explicitly to the Postgres role of the principal with object ID 00000000-0000-0000-0000-000000000001`},
+		{"no claim nor object ID", token(`{}`), "", "set user_name explicitly to the Postgres role of the principal terraform runs as"},
+		{"not a jwt", "opaque", "", "not a JWT"},
+		{"not json", token(`upn`), "", "decoding the access token"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			user, err := postgreUserFromToken(test.token)
+			if (test.err == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), test.err)) {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if user != test.user {
+				t.Fatalf("got user %q, expected %q", user, test.user)
+			}
+		})
+	}
+}
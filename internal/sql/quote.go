@@ -0,0 +1,97 @@
+package sql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Names are passed as bind parameters wherever the engine allows it. MS SQL statements which need a name in DDL
+// build it on the server with QuoteName(@param), Postgres DDL cannot take parameters so names are quoted here.
+
+// quotePostgreIdentifier returns name as a quoted Postgres identifier, e.g. a role or database name. Embedded
+// double quotes are doubled so the name cannot end the identifier, and the case of the name is kept.
+func quotePostgreIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quotePostgreIdentifiers quotes and comma separates the names.
+func quotePostgreIdentifiers(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quotePostgreIdentifier(name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// postgreGrantPrivilegesStatement grants privileges on the database to the account. The privileges are keywords
+// (see PostgreDatabasePrivileges) and written as they are.
+func postgreGrantPrivilegesStatement(privileges []string, database string, account string) string {
+	return "GRANT " + strings.Join(privileges, ", ") + " ON DATABASE " + quotePostgreIdentifier(database) + " TO " + quotePostgreIdentifier(account) + ";"
+}
+
+// postgreRevokePrivilegesStatement is the counterpart of postgreGrantPrivilegesStatement.
+func postgreRevokePrivilegesStatement(privileges []string, database string, account string) string {
+	return "REVOKE " + strings.Join(privileges, ", ") + " ON DATABASE " + quotePostgreIdentifier(database) + " FROM " + quotePostgreIdentifier(account) + ";"
+}
+
+// postgreGrantRolesStatement makes the account a member of all the given roles at once.
+func postgreGrantRolesStatement(roles []string, account string) string {
+	return "GRANT " + quotePostgreIdentifiers(roles) + " TO " + quotePostgreIdentifier(account) + ";"
+}
+
+// postgreRevokeRolesStatement revokes all the given roles from the account at once.
+func postgreRevokeRolesStatement(roles []string, account string) string {
+	return "REVOKE " + quotePostgreIdentifiers(roles) + " FROM " + quotePostgreIdentifier(account) + ";"
+}
+
+// postgreAlterRoleStatement sets a role option without arguments such as LOGIN or NOLOGIN, which must be a constant.
+func postgreAlterRoleStatement(account string, option string) string {
+	return "ALTER ROLE " + quotePostgreIdentifier(account) + " " + option + ";"
+}
+
+func postgreDropRoleStatement(account string) string {
+	return "DROP ROLE " + quotePostgreIdentifier(account) + ";"
+}
+
+// postgreReassignOwnedStatement transfers the objects the account owns in the current database to owner, or to the
+// current user when owner is empty.
+func postgreReassignOwnedStatement(account string, owner string) string {
+	target := "CURRENT_USER"
+	if owner != "" {
+		target = quotePostgreIdentifier(owner)
+	}
+
+	return "REASSIGN OWNED BY " + quotePostgreIdentifier(account) + " TO " + target + ";"
+}
+
+// postgreDropOwnedStatement drops the objects still owned by the account in the current database and revokes its
+// privileges there.
+func postgreDropOwnedStatement(account string) string {
+	return "DROP OWNED BY " + quotePostgreIdentifier(account) + ";"
+}
+
+// postgreRoleOptionsStatement sets the attributes of the account which are not about logging in.
+func postgreRoleOptionsStatement(account string, options PostgreRoleOptions) string {
+	flag := func(enabled bool, option string) string {
+		if enabled {
+			return " " + option
+		}
+		return " NO" + option
+	}
+
+	return "ALTER ROLE " + quotePostgreIdentifier(account) + " WITH CONNECTION LIMIT " + strconv.FormatInt(options.ConnectionLimit, 10) +
+		flag(options.CreateDatabase, "CREATEDB") + flag(options.CreateRole, "CREATEROLE") + flag(options.Inherit, "INHERIT") + ";"
+}
+
+// postgreSetFromCurrentStatement stores the value the setting has in the current transaction as the default of the
+// account. The value is set with set_config first, which parses it like the configuration file does, so list
+// settings such as search_path need no quoting.
+func postgreSetFromCurrentStatement(account string, setting string) string {
+	return "ALTER ROLE " + quotePostgreIdentifier(account) + " SET " + quotePostgreIdentifier(setting) + " FROM CURRENT;"
+}
+
+// postgreResetStatement removes the default of the setting from the account.
+func postgreResetStatement(account string, setting string) string {
+	return "ALTER ROLE " + quotePostgreIdentifier(account) + " RESET " + quotePostgreIdentifier(setting) + ";"
+}
@@ -0,0 +1,257 @@
+package sql
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// postgreTokens splits a statement into its quoted identifiers and literals, which are returned unescaped, and the
+// skeleton of the statement with each quoted token replaced by a placeholder. ok is false if a quote is not closed.
+func postgreTokens(statement string) (skeleton string, tokens []string, ok bool) {
+	var sb strings.Builder
+	for i := 0; i < len(statement); i++ {
+		quote := statement[i]
+		if quote != '"' && quote != '\'' {
+			sb.WriteByte(quote)
+			continue
+		}
+
+		var token strings.Builder
+		closed := false
+		for i++; i < len(statement); i++ {
+			if statement[i] != quote {
+				token.WriteByte(statement[i])
+				continue
+			}
+
+			if i+1 < len(statement) && statement[i+1] == quote {
+				token.WriteByte(quote)
+				i++
+				continue
+			}
+
+			closed = true
+			break
+		}
+
+		if !closed {
+			return "", nil, false
+		}
+
+		sb.WriteString("?")
+		tokens = append(tokens, token.String())
+	}
+
+	return sb.String(), tokens, true
+}
+
+// checkPostgreStatement verifies that the names end up as exactly the quoted tokens of the statement and that they
+// do not change the statement around them.
+func checkPostgreStatement(t *testing.T, build func(names ...string) string, names ...string) {
+	t.Helper()
+
+	benign := make([]string, len(names))
+	for i := range names {
+		benign[i] = "name"
+	}
+	expected, _, _ := postgreTokens(build(benign...))
+
+	statement := build(names...)
+	skeleton, tokens, ok := postgreTokens(statement)
+	if !ok {
+		t.Fatalf("unterminated quote in %q", statement)
+	}
+
+	if skeleton != expected {
+		t.Fatalf("statement %q has skeleton %q, expected %q", statement, skeleton, expected)
+	}
+
+	if len(tokens) != len(names) {
+		t.Fatalf("statement %q has %d quoted tokens, expected %d", statement, len(tokens), len(names))
+	}
+
+	for i, name := range names {
+		if tokens[i] != name {
+			t.Fatalf("statement %q quotes %q, expected %q", statement, tokens[i], name)
+		}
+	}
+}
+
+func FuzzPostgreStatements(f *testing.F) {
+	f.Add("pg_read_all_data", "my-database", "user@contoso.com")
+	f.Add(`x"; DROP ROLE admin; --`, `'`, `""`)
+	f.Add("CONNECT", "", `\"`)
+	f.Add("role", "db$1", "Robert'); DROP TABLE students;--")
+
+	f.Fuzz(func(t *testing.T, role string, database string, account string) {
+		if strings.ContainsRune(role+database+account, 0) {
+			t.Skip("Postgres does not allow NUL in identifiers")
+		}
+
+		checkPostgreStatement(t, func(names ...string) string { return postgreGrantRolesStatement(names[:2], names[2]) }, role, database, account)
+		checkPostgreStatement(t, func(names ...string) string { return postgreRevokeRolesStatement(names[:2], names[2]) }, role, database, account)
+		checkPostgreStatement(t, func(names ...string) string {
+			return postgreGrantPrivilegesStatement(PostgreDatabasePrivileges, names[0], names[1])
+		}, database, account)
+		checkPostgreStatement(t, func(names ...string) string {
+			return postgreRevokePrivilegesStatement(PostgreDatabasePrivileges, names[0], names[1])
+		}, database, account)
+		checkPostgreStatement(t, func(names ...string) string { return postgreAlterRoleStatement(names[0], "NOLOGIN") }, account)
+		checkPostgreStatement(t, func(names ...string) string { return postgreDropRoleStatement(names[0]) }, account)
+		if role != "" {
+			checkPostgreStatement(t, func(names ...string) string { return postgreReassignOwnedStatement(names[0], names[1]) }, account, role)
+		}
+		checkPostgreStatement(t, func(names ...string) string { return postgreDropOwnedStatement(names[0]) }, account)
+		checkPostgreStatement(t, func(names ...string) string {
+			return postgreRoleOptionsStatement(names[0], PostgreRoleOptions{ConnectionLimit: -1, Inherit: true})
+		}, account)
+		checkPostgreStatement(t, func(names ...string) string { return postgreSetFromCurrentStatement(names[0], names[1]) }, account, role)
+		checkPostgreStatement(t, func(names ...string) string { return postgreResetStatement(names[0], names[1]) }, account, role)
+	})
+}
+
+func FuzzQuotePostgreIdentifier(f *testing.F) {
+	f.Add("name")
+	f.Add(`"`)
+	f.Add(`a""b`)
+
+	f.Fuzz(func(t *testing.T, name string) {
+		quoted := quotePostgreIdentifier(name)
+		_, tokens, ok := postgreTokens(quoted)
+		if !ok || len(tokens) != 1 || tokens[0] != name {
+			t.Fatalf("%q does not quote %q", quoted, name)
+		}
+	})
+}
+
+func FuzzPermissionPattern(f *testing.F) {
+	f.Add("SELECT")
+	f.Add("VIEW DEFINITION")
+	f.Add("SELECT; DROP TABLE x")
+	f.Add("SELECT'")
+
+	f.Fuzz(func(t *testing.T, permission string) {
+		// Permissions are written into the statement unquoted, so anything matching must be plain words
+		if !PermissionPattern.MatchString(permission) {
+			return
+		}
+
+		for _, r := range permission {
+			if (r < 'A' || r > 'Z') && r != ' ' {
+				t.Fatalf("permission %q contains %q", permission, r)
+			}
+		}
+	})
+}
+
+// mssqlTokens splits a T-SQL statement into its string literals, which are returned unescaped, and the skeleton of
+// the statement with each literal replaced by a placeholder. ok is false if a quote is not closed.
+func mssqlTokens(statement string) (skeleton string, tokens []string, ok bool) {
+	var sb strings.Builder
+	for i := 0; i < len(statement); i++ {
+		if statement[i] != '\'' {
+			sb.WriteByte(statement[i])
+			continue
+		}
+
+		var token strings.Builder
+		closed := false
+		for i++; i < len(statement); i++ {
+			if statement[i] != '\'' {
+				token.WriteByte(statement[i])
+				continue
+			}
+
+			if i+1 < len(statement) && statement[i+1] == '\'' {
+				token.WriteByte('\'')
+				i++
+				continue
+			}
+
+			closed = true
+			break
+		}
+
+		if !closed {
+			return "", nil, false
+		}
+
+		sb.WriteString("?")
+		tokens = append(tokens, token.String())
+	}
+
+	return sb.String(), tokens, true
+}
+
+// checkMssqlStatement verifies that the quotes of the statement are closed and that each EXEC of @sql is guarded
+// against the NULL QuoteName returns for names which are too long.
+func checkMssqlStatement(t *testing.T, statement string) {
+	t.Helper()
+
+	skeleton, _, ok := mssqlTokens(statement)
+	if !ok {
+		t.Fatalf("unterminated quote in %q", statement)
+	}
+
+	execs := strings.Count(skeleton, "EXEC (@sql)")
+	if guards := strings.Count(skeleton, "IF @sql IS NULL\n\t\t\t\tTHROW"); guards != execs {
+		t.Fatalf("statement %q has %d EXEC (@sql) but %d NULL guards", statement, execs, guards)
+	}
+}
+
+func TestMssqlStatements(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		statement string
+		execs     int
+	}{
+		{"exec", "DECLARE @sql nvarchar(max) = ''\n" + mssqlExec, 1},
+		{"server role add", mssqlServerRoleStatement("ADD"), 1},
+		{"server role drop", mssqlServerRoleStatement("DROP"), 1},
+		{"grant", mssqlPermissionStatement("GRANT", []string{"SELECT", "VIEW DEFINITION"}, "TO", " WITH GRANT OPTION"), 1},
+		{"deny", mssqlPermissionStatement("DENY", []string{"SELECT"}, "TO", ""), 1},
+		{"revoke", mssqlPermissionStatement("REVOKE", []string{"SELECT"}, "FROM", " CASCADE"), 1},
+		{"create user with sid", CreateMssqlConnection(mssqlServer{}, "", "", "", "", CreateWithSid).createUserStatement(), 0},
+		{"create user from external provider", CreateMssqlConnection(mssqlServer{}, "", "", "", "", CreateFromExternalProvider).createUserStatement(), 0},
+		{"create user with object id", CreateMssqlConnection(mssqlServer{}, "", "", "", "", CreateWithObjectId).createUserStatement(), 0},
+		{"role member", "SET @sql = " + mssqlServer{}.roleMemberStatement("ADD", "@role", "@member"), 0},
+		{"synapse role member", "SET @sql = " + mssqlServer{flavor: SynapseDedicated}.roleMemberStatement("DROP", "@role", "@member"), 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			checkMssqlStatement(t, test.statement)
+
+			skeleton, _, _ := mssqlTokens(test.statement)
+			if execs := strings.Count(skeleton, "EXEC (@sql)"); execs != test.execs {
+				t.Fatalf("statement %q has %d EXEC (@sql), expected %d", test.statement, execs, test.execs)
+			}
+		})
+	}
+}
+
+func FuzzMssqlPermissionStatement(f *testing.F) {
+	f.Add("SELECT")
+	f.Add("VIEW DEFINITION")
+	f.Add("SELECT' + @principal + '")
+
+	f.Fuzz(func(t *testing.T, permission string) {
+		// Anything not matching is rejected before the statement is built
+		if !PermissionPattern.MatchString(permission) {
+			return
+		}
+
+		expected, _, _ := mssqlTokens(mssqlPermissionStatement("GRANT", []string{"SELECT"}, "TO", ""))
+
+		statement := mssqlPermissionStatement("GRANT", []string{permission}, "TO", "")
+		checkMssqlStatement(t, statement)
+
+		skeleton, tokens, _ := mssqlTokens(statement)
+		if skeleton != expected {
+			t.Fatalf("statement %q has skeleton %q, expected %q", statement, skeleton, expected)
+		}
+
+		if !slices.Contains(tokens, "GRANT "+permission) {
+			t.Fatalf("statement %q does not grant %q", statement, permission)
+		}
+	})
+}
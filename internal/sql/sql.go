@@ -3,21 +3,29 @@ package sql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
+// connector opens connections to a database, it is all Execute and the query functions need.
+type connector interface {
+	getConnectionString() string
+	createConnection(context.Context) (*sql.DB, error)
+}
+
 type SqlConnection interface {
+	connector
 	CreateAccount(context.Context, *diag.Diagnostics)
 	DropAccount(ctx context.Context, diags *diag.Diagnostics)
 	Id() string
-	getConnectionString() string
-	createConnection(context.Context) (*sql.DB, error)
 }
 
-func Execute(ctx context.Context, c SqlConnection, diags *diag.Diagnostics, command string, args ...interface{}) {
+func Execute(ctx context.Context, c connector, diags *diag.Diagnostics, command string, args ...interface{}) {
 	conn, err := c.createConnection(ctx)
 	if err != nil {
 		diags.AddError("error", err.Error())
@@ -32,3 +40,120 @@ func Execute(ctx context.Context, c SqlConnection, diags *diag.Diagnostics, comm
 		diags.AddError("statement error", fmt.Sprintf("error executing statement (%s) (%s): %s", command, c.getConnectionString(), err))
 	}
 }
+
+// QueryRow runs a query expected to return at most one row and scans it into dest, reporting whether a row was found.
+func QueryRow(ctx context.Context, c connector, diags *diag.Diagnostics, query string, args []interface{}, dest ...interface{}) bool {
+	conn, err := c.createConnection(ctx)
+	if err != nil {
+		diags.AddError("error", err.Error())
+		return false
+	}
+	defer conn.Close()
+
+	tflog.Debug(ctx, fmt.Sprintf("Executing query %q..", query))
+
+	err = conn.QueryRowContext(ctx, query, args...).Scan(dest...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+	if err != nil {
+		diags.AddError("query error", fmt.Sprintf("error executing query (%s) (%s): %s", query, c.getConnectionString(), err))
+		return false
+	}
+
+	return true
+}
+
+// Query runs a query and calls scan for every row returned.
+func Query(ctx context.Context, c connector, diags *diag.Diagnostics, query string, args []interface{}, scan func(*sql.Rows) error) {
+	conn, err := c.createConnection(ctx)
+	if err != nil {
+		diags.AddError("error", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	tflog.Debug(ctx, fmt.Sprintf("Executing query %q..", query))
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		diags.AddError("query error", fmt.Sprintf("error executing query (%s) (%s): %s", query, c.getConnectionString(), err))
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err = scan(rows); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = rows.Err()
+	}
+	if err != nil {
+		diags.AddError("query error", fmt.Sprintf("error reading results of query (%s) (%s): %s", query, c.getConnectionString(), err))
+	}
+}
+
+// statement is a command with its arguments, run by executeInTransaction.
+type statement struct {
+	command string
+	args    []interface{}
+}
+
+// executeInTransaction runs the statements on one connection in a single transaction, which is rolled back when one
+// of them fails so the database is left as it was.
+func executeInTransaction(ctx context.Context, c connector, diags *diag.Diagnostics, statements []statement) {
+	if len(statements) == 0 {
+		return
+	}
+
+	conn, err := c.createConnection(ctx)
+	if err != nil {
+		diags.AddError("error", err.Error())
+		return
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		diags.AddError("transaction error", fmt.Sprintf("error starting transaction (%s): %s", c.getConnectionString(), err))
+		return
+	}
+
+	for _, s := range statements {
+		tflog.Debug(ctx, fmt.Sprintf("Executing command %q..", s.command))
+
+		if _, err = tx.ExecContext(ctx, s.command, s.args...); err != nil {
+			tx.Rollback()
+			diags.AddError("statement error", fmt.Sprintf("error executing statement (%s) (%s), the transaction was rolled back: %s", s.command, c.getConnectionString(), err))
+			return
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		diags.AddError("transaction error", fmt.Sprintf("error committing transaction (%s): %s", c.getConnectionString(), err))
+	}
+}
+
+// parseServerId splits an ID of a server level object (<sql_server_dns>:<port>/<name>) into its parts, label names
+// the object in errors.
+func parseServerId(id string, label string) (string, int64, string, error) {
+	server, name, ok := strings.Cut(id, "/")
+	if !ok || name == "" {
+		return "", 0, "", fmt.Errorf("expected an ID of the form <sql_server_dns>:<port>/<%s>, got %q", label, id)
+	}
+
+	server, portValue, ok := strings.Cut(server, ":")
+	if !ok || server == "" {
+		return "", 0, "", fmt.Errorf("expected an ID of the form <sql_server_dns>:<port>/<%s>, got %q", label, id)
+	}
+
+	port, err := strconv.ParseInt(portValue, 10, 64)
+	if err != nil {
+		return "", 0, "", fmt.Errorf("invalid port in ID %q: %s", id, err)
+	}
+
+	return server, port, name, nil
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
@@ -28,3 +29,29 @@ func ValueStringOrDefault(value basetypes.StringValue, defaultValue string) stri
 
 	return value.ValueString()
 }
+
+// Difference returns the values of a which are not present in b.
+func Difference(a []string, b []string) []string {
+	var result []string
+
+	for _, v := range a {
+		if !slices.Contains(b, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// Intersection returns the values of a which are also present in b.
+func Intersection(a []string, b []string) []string {
+	var result []string
+
+	for _, v := range a {
+		if slices.Contains(b, v) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}